@@ -0,0 +1,33 @@
+package main
+
+import (
+	"io"
+	"os"
+	"strings"
+	"testing"
+
+	"GoProject/1_moduleFoundation/2_HTTPClient/cassette"
+	"GoProject/1_moduleFoundation/2_HTTPClient/cassette/cassettetest"
+)
+
+// O exemplo chama o Google com http.Get; o teste responde pela fixture
+// compartilhada em 2_HTTPClient/testdata e roda o main sem rede.
+func TestExample(t *testing.T) {
+	cassettetest.DefaultTransport(t, "../testdata/fixtures/google.json", cassette.Options{Mode: cassette.ModeReplay})
+
+	// O main escreve o HTML na saída padrão
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	stdout := os.Stdout
+	os.Stdout = w
+	main()
+	w.Close()
+	os.Stdout = stdout
+
+	out, _ := io.ReadAll(r)
+	if !strings.Contains(string(out), "<title>Google</title>") {
+		t.Errorf("saída sem o HTML da página:\n%s", out)
+	}
+}
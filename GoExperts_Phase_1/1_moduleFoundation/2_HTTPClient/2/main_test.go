@@ -0,0 +1,15 @@
+package main
+
+import (
+	"testing"
+
+	"GoProject/1_moduleFoundation/2_HTTPClient/cassette"
+	"GoProject/1_moduleFoundation/2_HTTPClient/cassette/cassettetest"
+)
+
+// O http.Client do exemplo não tem Transport: usa o http.DefaultTransport,
+// trocado pela fixture compartilhada. Requisição fora da fixture reprova o teste.
+func TestExample(t *testing.T) {
+	cassettetest.DefaultTransport(t, "../testdata/fixtures/google.json", cassette.Options{Mode: cassette.ModeReplay})
+	main() // Entra em panic se a requisição falhar
+}
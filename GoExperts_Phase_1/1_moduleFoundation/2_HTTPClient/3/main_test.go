@@ -0,0 +1,31 @@
+package main
+
+import (
+	"net/http"
+	"testing"
+
+	"GoProject/1_moduleFoundation/2_HTTPClient/cassette"
+	"GoProject/1_moduleFoundation/2_HTTPClient/cassette/cassettetest"
+)
+
+// roundTripperFunc adapta uma função para http.RoundTripper
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) { return f(req) }
+
+// O exemplo monta a requisição com http.NewRequest e o header Accept; a
+// resposta vem da fixture compartilhada, sem rede
+func TestExample(t *testing.T) {
+	rec := cassettetest.DefaultTransport(t, "../testdata/fixtures/google.json", cassette.Options{Mode: cassette.ModeReplay})
+
+	var accept string
+	http.DefaultTransport = roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		accept = req.Header.Get("Accept")
+		return rec.RoundTrip(req)
+	})
+
+	main() // Entra em panic se a requisição falhar
+	if accept != "application/json" {
+		t.Errorf("Accept = %q; expect application/json", accept)
+	}
+}
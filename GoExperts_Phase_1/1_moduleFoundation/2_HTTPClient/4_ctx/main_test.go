@@ -0,0 +1,32 @@
+package main
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"GoProject/1_moduleFoundation/2_HTTPClient/cassette"
+	"GoProject/1_moduleFoundation/2_HTTPClient/cassette/cassettetest"
+)
+
+// roundTripperFunc adapta uma função para http.RoundTripper
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) { return f(req) }
+
+// O exemplo limita a requisição com context.WithTimeout; o teste confere que o
+// deadline chega na requisição e responde pela fixture compartilhada, sem rede
+func TestExample(t *testing.T) {
+	rec := cassettetest.DefaultTransport(t, "../testdata/fixtures/google.json", cassette.Options{Mode: cassette.ModeReplay})
+
+	var deadline time.Time
+	http.DefaultTransport = roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		deadline, _ = req.Context().Deadline()
+		return rec.RoundTrip(req)
+	})
+
+	main() // Entra em panic se a requisição falhar
+	if deadline.IsZero() || deadline.After(time.Now().Add(time.Second)) {
+		t.Errorf("deadline da requisição = %v; expect no máximo 1s à frente", deadline)
+	}
+}
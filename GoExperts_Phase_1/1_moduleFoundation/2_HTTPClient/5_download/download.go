@@ -0,0 +1,441 @@
+package main
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+var (
+	errChecksum = errors.New("checksum não confere")
+	errChanged  = errors.New("o arquivo mudou no servidor durante o download")
+)
+
+// options configura o download
+type options struct {
+	Parallel     int       // Conexões simultâneas quando o servidor aceita Range (padrão 4)
+	MinChunkSize int64     // Menor parte baixada por conexão (padrão 1 MiB)
+	SHA256       string    // Hex esperado do arquivo completo (vazio não verifica)
+	Progress     io.Writer // Recebe a linha de progresso (nil desliga)
+}
+
+// result resume o download concluído
+type result struct {
+	Path    string
+	Size    int64
+	Resumed int64 // Bytes que já estavam no .part de uma execução anterior
+	Chunks  int
+	SHA256  string
+}
+
+// state é gravado em <destino>.part.json para retomar o download.
+// Done só avança depois do WriteAt: o arquivo nunca tem menos do que o state diz.
+type state struct {
+	URL          string   `json:"url"`
+	ETag         string   `json:"etag,omitempty"`
+	LastModified string   `json:"last_modified,omitempty"`
+	Size         int64    `json:"size"` // -1 = desconhecido
+	Ranges       bool     `json:"ranges"`
+	Chunks       []*chunk `json:"chunks"`
+}
+
+// chunk é um intervalo do arquivo baixado por uma conexão
+type chunk struct {
+	Start int64 `json:"start"`
+	End   int64 `json:"end"` // Inclusive; -1 = até o fim da resposta
+	Done  int64 `json:"done"`
+}
+
+// remote é o que o HEAD conta sobre o arquivo
+type remote struct {
+	size         int64
+	ranges       bool
+	etag         string
+	lastModified string
+}
+
+// ifRange devolve o validador para o If-Range: ETag forte ou Last-Modified
+func (s *state) ifRange() string {
+	if s.ETag != "" && !strings.HasPrefix(s.ETag, "W/") {
+		return s.ETag
+	}
+	return s.LastModified
+}
+
+// matches diz se o .part salvo é do mesmo arquivo que o servidor tem agora
+func (s *state) matches(url string, r remote) bool {
+	return s.URL == url && s.Size == r.size && s.Ranges && r.ranges &&
+		s.ETag == r.etag && s.LastModified == r.lastModified && s.ifRange() != ""
+}
+
+// downloader guarda o estado compartilhado pelas conexões
+type downloader struct {
+	client *http.Client
+	url    string
+	file   *os.File
+
+	mu    sync.Mutex // Protege st.Chunks[*].Done
+	st    *state
+	bytes atomic.Int64 // Bytes baixados nesta execução (progresso)
+}
+
+// download baixa url para dest. O conteúdo vai para dest.part e só é renomeado
+// para dest depois de completo e conferido; se falhar ou for interrompido, o
+// .part e o .part.json ficam para a próxima execução continuar de onde parou.
+func download(ctx context.Context, client *http.Client, url, dest string, opts options) (result, error) {
+	if opts.Parallel <= 0 {
+		opts.Parallel = 4
+	}
+	if opts.MinChunkSize <= 0 {
+		opts.MinChunkSize = 1 << 20
+	}
+	part, statePath := dest+".part", dest+".part.json"
+
+	info, err := probe(ctx, client, url)
+	if err != nil {
+		return result{}, err
+	}
+
+	st, resumed := loadState(statePath), true
+	if _, err := os.Stat(part); st == nil || err != nil || !st.matches(url, info) {
+		st, resumed = newState(url, info, opts), false
+	}
+
+	file, err := os.OpenFile(part, os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return result{}, err
+	}
+	defer file.Close()
+	if !resumed {
+		// Começa do zero; em partes, o arquivo já nasce com o tamanho final
+		size := int64(0)
+		if len(st.Chunks) > 1 {
+			size = st.Size
+		}
+		if err := file.Truncate(size); err != nil {
+			return result{}, err
+		}
+	}
+
+	d := &downloader{client: client, url: url, file: file, st: st}
+	res := result{Path: dest, Chunks: len(st.Chunks), Resumed: d.completed()}
+
+	stop := d.report(opts.Progress, statePath)
+	err = d.run(ctx)
+	stop()
+	if err != nil {
+		if errors.Is(err, errChanged) {
+			os.Remove(part)
+			os.Remove(statePath)
+			return res, fmt.Errorf("%w; rode de novo para baixar a nova versão", err)
+		}
+		return res, err // .part e state ficam para retomar
+	}
+
+	if err := file.Sync(); err != nil {
+		return res, err
+	}
+	if err := file.Close(); err != nil {
+		return res, err
+	}
+
+	res.Size, res.SHA256, err = hashFile(part)
+	if err != nil {
+		return res, err
+	}
+	if st.Size >= 0 && res.Size != st.Size {
+		return res, fmt.Errorf("tamanho %d; o servidor anunciou %d", res.Size, st.Size)
+	}
+	if opts.SHA256 != "" && !strings.EqualFold(opts.SHA256, res.SHA256) {
+		// Um .part corrompido não deve ser retomado
+		os.Remove(part)
+		os.Remove(statePath)
+		return res, fmt.Errorf("%w: esperado %s, recebido %s", errChecksum, opts.SHA256, res.SHA256)
+	}
+
+	if err := os.Rename(part, dest); err != nil {
+		return res, err
+	}
+	os.Remove(statePath)
+	return res, nil
+}
+
+// probe faz um HEAD para saber tamanho, suporte a Range e validadores.
+// Servidor que não aceita HEAD vira download simples, sem partes nem retomada.
+func probe(ctx context.Context, client *http.Client, url string) (remote, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, url, nil)
+	if err != nil {
+		return remote{}, err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return remote{}, err
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return remote{size: -1}, nil
+	}
+	return remote{
+		size:         resp.ContentLength,
+		ranges:       strings.Contains(resp.Header.Get("Accept-Ranges"), "bytes") && resp.ContentLength > 0,
+		etag:         resp.Header.Get("ETag"),
+		lastModified: resp.Header.Get("Last-Modified"),
+	}, nil
+}
+
+// newState divide o arquivo em até opts.Parallel partes de pelo menos opts.MinChunkSize
+func newState(url string, info remote, opts options) *state {
+	st := &state{URL: url, ETag: info.etag, LastModified: info.lastModified, Size: info.size, Ranges: info.ranges}
+	if !info.ranges {
+		st.Chunks = []*chunk{{Start: 0, End: max(info.size-1, -1)}} // -1: tamanho desconhecido
+		return st
+	}
+
+	n := min(int64(opts.Parallel), max(info.size/opts.MinChunkSize, 1))
+	size := (info.size + n - 1) / n
+	for start := int64(0); start < info.size; start += size {
+		st.Chunks = append(st.Chunks, &chunk{Start: start, End: min(start+size, info.size) - 1})
+	}
+	return st
+}
+
+// loadState lê o state salvo; nil se não existe ou está corrompido
+func loadState(path string) *state {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+	var st state
+	if json.Unmarshal(data, &st) != nil || len(st.Chunks) == 0 {
+		return nil
+	}
+	return &st
+}
+
+// save grava o state por arquivo temporário + rename, como o download
+func (d *downloader) save(path string) error {
+	d.mu.Lock()
+	data, err := json.Marshal(d.st)
+	d.mu.Unlock()
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(path+".tmp", data, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(path+".tmp", path)
+}
+
+// completed soma os bytes já gravados de todas as partes
+func (d *downloader) completed() int64 {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	var total int64
+	for _, c := range d.st.Chunks {
+		total += c.Done
+	}
+	return total
+}
+
+// run baixa as partes em paralelo; a primeira falha cancela as outras
+func (d *downloader) run(ctx context.Context) error {
+	ctx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+
+	var wg sync.WaitGroup
+	for _, c := range d.st.Chunks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := d.fetch(ctx, c); err != nil {
+				cancel(err)
+			}
+		}()
+	}
+	wg.Wait()
+	return context.Cause(ctx)
+}
+
+// fetch baixa o que falta da parte c
+func (d *downloader) fetch(ctx context.Context, c *chunk) error {
+	d.mu.Lock()
+	from := c.Start + c.Done
+	d.mu.Unlock()
+	if c.End >= 0 && from > c.End {
+		return nil // Parte já completa
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, d.url, nil)
+	if err != nil {
+		return err
+	}
+	ranged := d.st.Ranges && (from > 0 || len(d.st.Chunks) > 1)
+	if ranged {
+		req.Header.Set("Range", fmt.Sprintf("bytes=%d-%d", from, c.End))
+		// Se o arquivo mudou, o servidor ignora o Range e manda tudo (200)
+		req.Header.Set("If-Range", d.st.ifRange())
+	}
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusPartialContent && ranged:
+		if start, ok := contentRangeStart(resp.Header.Get("Content-Range")); !ok || start != from {
+			return fmt.Errorf("Content-Range %q; esperado início em %d", resp.Header.Get("Content-Range"), from)
+		}
+	case resp.StatusCode == http.StatusOK && ranged && len(d.st.Chunks) > 1:
+		return errChanged
+	case resp.StatusCode == http.StatusOK:
+		if from > 0 {
+			// Só uma parte: recomeça do zero com a resposta completa
+			if err := d.file.Truncate(0); err != nil {
+				return err
+			}
+			d.mu.Lock()
+			c.Done = 0
+			d.mu.Unlock()
+			from = 0
+		}
+	default:
+		return fmt.Errorf("GET %s: %s", d.url, resp.Status)
+	}
+
+	body := io.Reader(resp.Body)
+	if c.End >= 0 {
+		body = io.LimitReader(body, c.End-from+1)
+	}
+	buf := make([]byte, 32<<10)
+	for {
+		n, err := body.Read(buf)
+		if n > 0 {
+			if _, err := d.file.WriteAt(buf[:n], from); err != nil {
+				return err
+			}
+			from += int64(n)
+			d.mu.Lock()
+			c.Done += int64(n)
+			d.mu.Unlock()
+			d.bytes.Add(int64(n))
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+	}
+	if c.End >= 0 && from <= c.End {
+		return fmt.Errorf("parte %d-%d: %w", c.Start, c.End, io.ErrUnexpectedEOF)
+	}
+	return nil
+}
+
+// contentRangeStart lê o início de "bytes 100-199/1000"
+func contentRangeStart(header string) (int64, bool) {
+	spec, ok := strings.CutPrefix(header, "bytes ")
+	if !ok {
+		return 0, false
+	}
+	start, _, ok := strings.Cut(spec, "-")
+	if !ok {
+		return 0, false
+	}
+	n, err := strconv.ParseInt(start, 10, 64)
+	return n, err == nil
+}
+
+// report escreve o progresso a cada 500ms e salva o state (para retomar mesmo
+// se o processo morrer). A função devolvida para o relatório e salva uma última vez.
+func (d *downloader) report(w io.Writer, statePath string) (stop func()) {
+	done := make(chan struct{})
+	finished := make(chan struct{})
+	start := time.Now()
+	resumed := d.completed()
+
+	line := func() {
+		if w == nil {
+			return
+		}
+		got := d.bytes.Load()
+		speed := float64(got) / max(time.Since(start).Seconds(), 0.001)
+		total := "?"
+		if d.st.Size >= 0 {
+			total = formatBytes(d.st.Size)
+			fmt.Fprintf(w, "\r%s / %s (%.0f%%) %s/s   ", formatBytes(resumed+got), total, float64(resumed+got)*100/float64(max(d.st.Size, 1)), formatBytes(int64(speed)))
+			return
+		}
+		fmt.Fprintf(w, "\r%s / %s %s/s   ", formatBytes(resumed+got), total, formatBytes(int64(speed)))
+	}
+
+	go func() {
+		defer close(finished)
+		ticker := time.NewTicker(500 * time.Millisecond)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				line()
+				d.save(statePath)
+			case <-done:
+				line()
+				if w != nil {
+					fmt.Fprintln(w)
+				}
+				d.save(statePath)
+				return
+			}
+		}
+	}()
+	return func() {
+		close(done)
+		<-finished
+	}
+}
+
+// formatBytes escreve n em B, KiB, MiB ou GiB
+func formatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	value, suffix := float64(n), "B"
+	for _, s := range []string{"KiB", "MiB", "GiB", "TiB"} {
+		if value < unit {
+			break
+		}
+		value, suffix = value/unit, s
+	}
+	return fmt.Sprintf("%.1f %s", value, suffix)
+}
+
+// hashFile devolve o tamanho e o SHA-256 do arquivo
+func hashFile(path string) (int64, string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return 0, "", err
+	}
+	defer file.Close()
+
+	h := sha256.New()
+	n, err := io.Copy(h, file)
+	if err != nil {
+		return 0, "", err
+	}
+	return n, hex.EncodeToString(h.Sum(nil)), nil
+}
@@ -0,0 +1,255 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"math/rand/v2"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fileServer serve content com Range (http.ServeContent) e registra as requisições
+type fileServer struct {
+	mu       sync.Mutex
+	content  []byte
+	etag     string
+	noRanges bool // Responde sempre 200 sem Accept-Ranges
+	cutAfter int  // > 0: derruba cada resposta depois de tantos bytes
+	ranges   []string
+	served   int64 // Bytes de corpo enviados
+}
+
+func (s *fileServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	content, etag := s.content, s.etag
+	if r.Method == http.MethodGet {
+		s.ranges = append(s.ranges, r.Header.Get("Range"))
+	}
+	s.mu.Unlock()
+
+	cw := &countingWriter{ResponseWriter: w, server: s, limit: s.cutAfter}
+	if s.noRanges {
+		if r.Method == http.MethodHead {
+			return
+		}
+		cw.Write(content)
+		return
+	}
+	w.Header().Set("ETag", etag)
+	http.ServeContent(cw, r, "", time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), bytes.NewReader(content))
+}
+
+// countingWriter conta o corpo enviado e simula a conexão caindo após limit bytes
+type countingWriter struct {
+	http.ResponseWriter
+	server  *fileServer
+	limit   int
+	written int
+}
+
+func (w *countingWriter) Write(p []byte) (int, error) {
+	if w.limit > 0 && w.written+len(p) > w.limit {
+		p = p[:w.limit-w.written]
+		w.count(p)
+		w.ResponseWriter.Write(p)
+		w.ResponseWriter.(http.Flusher).Flush()
+		panic(http.ErrAbortHandler)
+	}
+	w.count(p)
+	return w.ResponseWriter.Write(p)
+}
+
+func (w *countingWriter) count(p []byte) {
+	w.written += len(p)
+	w.server.mu.Lock()
+	w.server.served += int64(len(p))
+	w.server.mu.Unlock()
+}
+
+func (s *fileServer) reset() (ranges []string, served int64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	ranges, served = s.ranges, s.served
+	s.ranges, s.served = nil, 0
+	return ranges, served
+}
+
+func randomContent(n int) []byte {
+	b := make([]byte, n)
+	for i := range b {
+		b[i] = byte(rand.N(256))
+	}
+	return b
+}
+
+func sha256Hex(b []byte) string {
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:])
+}
+
+// checkDownloaded confere o arquivo final e que não sobrou .part nem state
+func checkDownloaded(t *testing.T, dest string, content []byte) {
+	t.Helper()
+	got, err := os.ReadFile(dest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, content) {
+		t.Errorf("conteúdo baixado difere (%d bytes; expect %d)", len(got), len(content))
+	}
+	for _, leftover := range []string{dest + ".part", dest + ".part.json"} {
+		if _, err := os.Stat(leftover); err == nil {
+			t.Errorf("%s não foi removido", filepath.Base(leftover))
+		}
+	}
+}
+
+func TestDownload(t *testing.T) {
+	content := randomContent(10000)
+
+	tests := []struct {
+		name     string
+		noRanges bool
+		parallel int
+		chunks   int
+		ranged   int // GETs com header Range
+	}{
+		{"uma conexão", false, 1, 1, 0},
+		{"em partes", false, 4, 4, 4},
+		{"partes limitadas pelo tamanho mínimo", false, 20, 9, 9},
+		{"servidor sem Range", true, 4, 1, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := &fileServer{content: content, etag: `"v1"`, noRanges: tt.noRanges}
+			ts := httptest.NewServer(server)
+			defer ts.Close()
+
+			dest := filepath.Join(t.TempDir(), "arquivo.bin")
+			opts := options{Parallel: tt.parallel, MinChunkSize: 1024, SHA256: sha256Hex(content)}
+			res, err := download(context.Background(), ts.Client(), ts.URL, dest, opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			checkDownloaded(t, dest, content)
+
+			ranges, served := server.reset()
+			ranged := 0
+			for _, r := range ranges {
+				if r != "" {
+					ranged++
+				}
+			}
+			if res.Chunks != tt.chunks || ranged != tt.ranged || served != int64(len(content)) {
+				t.Errorf("%d partes, %d GETs com Range, %d bytes enviados; expect %d, %d, %d", res.Chunks, ranged, served, tt.chunks, tt.ranged, len(content))
+			}
+			if res.Size != int64(len(content)) || res.SHA256 != sha256Hex(content) {
+				t.Errorf("result = %+v", res)
+			}
+		})
+	}
+}
+
+func TestDownloadResume(t *testing.T) {
+	content := randomContent(10000)
+
+	for _, parallel := range []int{1, 4} {
+		t.Run(strings.Repeat("c", parallel), func(t *testing.T) {
+			server := &fileServer{content: content, etag: `"v1"`, cutAfter: 1000}
+			ts := httptest.NewServer(server)
+			defer ts.Close()
+			dest := filepath.Join(t.TempDir(), "arquivo.bin")
+			opts := options{Parallel: parallel, MinChunkSize: 1024}
+
+			// 1ª execução: cada conexão cai depois de 1000 bytes
+			if _, err := download(context.Background(), ts.Client(), ts.URL, dest, opts); err == nil {
+				t.Fatal("download() sem erro com a conexão caindo")
+			}
+			if _, err := os.Stat(dest); err == nil {
+				t.Fatal("destino criado com o download incompleto")
+			}
+			server.reset()
+
+			// 2ª execução: só o que faltou
+			server.cutAfter = 0
+			res, err := download(context.Background(), ts.Client(), ts.URL, dest, opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			checkDownloaded(t, dest, content)
+
+			ranges, second := server.reset()
+			// Bytes em trânsito quando outra parte falhou se perdem; o resto é aproveitado
+			if res.Resumed < 1000 || res.Resumed+second != int64(len(content)) {
+				t.Errorf("retomado de %d, 2ª enviou %d; expect só o que faltava de %d", res.Resumed, second, len(content))
+			}
+			if parallel == 1 && (len(ranges) != 1 || ranges[0] != "bytes=1000-9999") {
+				t.Errorf("Range da retomada = %q; expect bytes=1000-9999", ranges)
+			}
+		})
+	}
+}
+
+func TestDownloadChangedOnServer(t *testing.T) {
+	server := &fileServer{content: randomContent(5000), etag: `"v1"`, cutAfter: 1000}
+	ts := httptest.NewServer(server)
+	defer ts.Close()
+	dest := filepath.Join(t.TempDir(), "arquivo.bin")
+
+	download(context.Background(), ts.Client(), ts.URL, dest, options{Parallel: 1})
+
+	// Nova versão: o .part da antiga não pode ser aproveitado
+	updated := randomContent(6000)
+	server.mu.Lock()
+	server.content, server.etag, server.cutAfter = updated, `"v2"`, 0
+	server.mu.Unlock()
+
+	res, err := download(context.Background(), ts.Client(), ts.URL, dest, options{Parallel: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkDownloaded(t, dest, updated)
+	if res.Resumed != 0 {
+		t.Errorf("retomado de %d bytes da versão antiga", res.Resumed)
+	}
+}
+
+func TestDownloadChecksum(t *testing.T) {
+	ts := httptest.NewServer(&fileServer{content: randomContent(3000), etag: `"v1"`})
+	defer ts.Close()
+	dest := filepath.Join(t.TempDir(), "arquivo.bin")
+
+	_, err := download(context.Background(), ts.Client(), ts.URL, dest, options{SHA256: sha256Hex([]byte("outro"))})
+	if !errors.Is(err, errChecksum) {
+		t.Fatalf("download() erro = %v; expect errChecksum", err)
+	}
+	for _, path := range []string{dest, dest + ".part", dest + ".part.json"} {
+		if _, err := os.Stat(path); err == nil {
+			t.Errorf("%s existe após checksum inválido", filepath.Base(path))
+		}
+	}
+}
+
+func TestOutputName(t *testing.T) {
+	tests := map[string]string{
+		"https://go.dev/dl/go1.24.3.src.tar.gz": "go1.24.3.src.tar.gz",
+		"https://exemplo.com/":                  "download",
+		"https://exemplo.com":                   "download",
+		"https://exemplo.com/a/b.zip?x=1":       "b.zip",
+	}
+	for rawURL, expect := range tests {
+		if got := outputName(rawURL); got != expect {
+			t.Errorf("outputName(%q) = %q; expect %q", rawURL, got, expect)
+		}
+	}
+}
@@ -0,0 +1,86 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"net/url"
+	"os"
+	"os/signal"
+	"path"
+	"time"
+
+	"GoProject/1_moduleFoundation/2_HTTPClient/httpClient"
+)
+
+// Baixa um arquivo direto para o disco, sem guardar o corpo na memória.
+//
+// go run ./1_moduleFoundation/2_HTTPClient/5_download https://go.dev/dl/go1.24.3.src.tar.gz
+// go run ./1_moduleFoundation/2_HTTPClient/5_download -o go.tar.gz -c 8 -sha256=<hex> <url>
+//
+// O conteúdo vai para <saída>.part e só vira <saída> depois de completo (e
+// conferido com -sha256). Se o download cair ou for interrompido (Ctrl+C), rode
+// o mesmo comando de novo: com Range, ele continua de onde parou. Se o servidor
+// anuncia Accept-Ranges, o arquivo é baixado em -c partes simultâneas.
+
+func main() {
+	out := flag.String("o", "", "arquivo de saída (padrão: o nome no fim da URL)")
+	parallel := flag.Int("c", 4, "conexões simultâneas quando o servidor aceita Range")
+	sum := flag.String("sha256", "", "SHA-256 esperado (hex); o download falha se não conferir")
+	timeout := flag.Duration("timeout", 30*time.Second, "espera máxima pelos headers de cada resposta")
+	quiet := flag.Bool("q", false, "não mostra o progresso")
+	flag.Parse()
+
+	if flag.NArg() != 1 {
+		fmt.Fprintln(os.Stderr, "uso: 5_download [flags] <url>")
+		flag.PrintDefaults()
+		os.Exit(2)
+	}
+	rawURL := flag.Arg(0)
+	if *out == "" {
+		*out = outputName(rawURL)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	// Sem Timeout total: um arquivo grande pode levar horas; o limite é por resposta
+	client := httpClient.New(httpClient.Options{
+		ResponseHeaderTimeout: *timeout,
+		UserAgent:             "5_download/1.0",
+		Retry:                 httpClient.RetryPolicy{MaxAttempts: 3, BaseDelay: 500 * time.Millisecond, MaxDelay: 5 * time.Second},
+	})
+
+	opts := options{Parallel: *parallel, SHA256: *sum, Progress: os.Stderr}
+	if *quiet {
+		opts.Progress = nil
+	}
+
+	res, err := download(ctx, client, rawURL, *out, opts)
+	if err != nil {
+		if ctx.Err() != nil {
+			log.Fatalf("Download interrompido; rode o mesmo comando para continuar")
+		}
+		log.Fatalf("Erro ao baixar %s: %v", rawURL, err)
+	}
+
+	fmt.Printf("%s salvo em %s (%d partes)\n", formatBytes(res.Size), res.Path, res.Chunks)
+	if res.Resumed > 0 {
+		fmt.Printf("retomado: %s já estavam baixados\n", formatBytes(res.Resumed))
+	}
+	fmt.Printf("sha256 %s\n", res.SHA256)
+}
+
+// outputName usa o último segmento do path da URL como nome do arquivo
+func outputName(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "download"
+	}
+	name := path.Base(u.Path)
+	if name == "." || name == "/" || name == "" {
+		return "download"
+	}
+	return name
+}
@@ -0,0 +1,260 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"GoProject/1_moduleFoundation/2_HTTPClient/httpClient"
+)
+
+// Faz uma requisição HTTP configurada por flags, no lugar de editar o 3/main.go a cada teste.
+//
+// go run ./1_moduleFoundation/2_HTTPClient/6_curl https://viacep.com.br/ws/01001000/json/
+// go run ./1_moduleFoundation/2_HTTPClient/6_curl -json -v https://viacep.com.br/ws/01001000/json/
+// go run ./1_moduleFoundation/2_HTTPClient/6_curl -X POST -H "X-Client-ID: eu" -d @ceps.json -json http://localhost:8080/ceps
+// echo '{"cep":"01001000"}' | go run ./1_moduleFoundation/2_HTTPClient/6_curl -d @- -json http://localhost:8080/addresses/verify
+//
+// -v mostra os headers enviados (>) e recebidos (<) de cada salto e o tempo de
+// cada fase (DNS, conexão, TLS, 1º byte) medido com httptrace, tudo no stderr.
+
+// errHTTPStatus é devolvido com -fail quando a resposta final é 4xx/5xx
+var errHTTPStatus = errors.New("resposta com erro")
+
+// config são as flags já interpretadas
+type config struct {
+	URL          string
+	Method       string
+	Headers      http.Header
+	Data         string // Corpo; "@arquivo" lê do arquivo e "@-" do stdin
+	Timeout      time.Duration
+	Follow       bool
+	MaxRedirects int
+	Output       string // Vazio ==> stdout
+	JSON         bool
+	Verbose      bool
+	Insecure     bool
+	Fail         bool
+}
+
+// headerFlags acumula os -H "Nome: valor"
+type headerFlags http.Header
+
+func (h headerFlags) String() string { return "" }
+
+func (h headerFlags) Set(value string) error {
+	name, v, ok := strings.Cut(value, ":")
+	if !ok || strings.TrimSpace(name) == "" {
+		return fmt.Errorf("header %q: use \"Nome: valor\"", value)
+	}
+	http.Header(h).Add(strings.TrimSpace(name), strings.TrimSpace(v))
+	return nil
+}
+
+func main() {
+	cfg, err := parseArgs(os.Args[1:], os.Stderr)
+	if err != nil {
+		os.Exit(2)
+	}
+
+	if err := run(context.Background(), cfg, os.Stdin, os.Stdout, os.Stderr); err != nil {
+		fmt.Fprintf(os.Stderr, "6_curl: %v\n", err)
+		if errors.Is(err, errHTTPStatus) {
+			os.Exit(22) // Mesmo código do curl --fail
+		}
+		os.Exit(1)
+	}
+}
+
+// parseArgs interpreta as flags; a URL é o único argumento posicional
+func parseArgs(args []string, stderr io.Writer) (config, error) {
+	cfg := config{Headers: http.Header{}}
+	fs := flag.NewFlagSet("6_curl", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.Usage = func() {
+		fmt.Fprintln(stderr, "uso: 6_curl [flags] <url>")
+		fs.PrintDefaults()
+	}
+
+	fs.StringVar(&cfg.Method, "X", "", "método HTTP (padrão GET, ou POST com -d)")
+	fs.Var(headerFlags(cfg.Headers), "H", "header \"Nome: valor\" (pode repetir)")
+	fs.StringVar(&cfg.Data, "d", "", "corpo da requisição; @arquivo lê do arquivo e @- do stdin")
+	fs.DurationVar(&cfg.Timeout, "timeout", 30*time.Second, "tempo máximo da chamada, com redirects e leitura do corpo (0 = sem limite)")
+	fs.BoolVar(&cfg.Follow, "L", false, "segue redirects (3xx)")
+	fs.IntVar(&cfg.MaxRedirects, "max-redirs", 10, "máximo de redirects seguidos com -L")
+	fs.StringVar(&cfg.Output, "o", "", "grava o corpo da resposta no arquivo em vez do stdout")
+	fs.BoolVar(&cfg.JSON, "json", false, "envia/aceita JSON e formata a resposta JSON")
+	fs.BoolVar(&cfg.Verbose, "v", false, "mostra headers e tempos (DNS, conexão, TLS, 1º byte) no stderr")
+	fs.BoolVar(&cfg.Insecure, "k", false, "não verifica o certificado TLS do servidor")
+	fs.BoolVar(&cfg.Fail, "fail", false, "sai com código 22 se a resposta for 4xx/5xx")
+
+	if err := fs.Parse(args); err != nil {
+		return cfg, err
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return cfg, errors.New("informe uma URL")
+	}
+	cfg.URL = fs.Arg(0)
+	if !strings.Contains(cfg.URL, "://") {
+		cfg.URL = "http://" + cfg.URL // Como o curl: "localhost:8080/status" vira http://
+	}
+
+	if cfg.Method == "" {
+		cfg.Method = http.MethodGet
+		if cfg.Data != "" {
+			cfg.Method = http.MethodPost
+		}
+	}
+	cfg.Method = strings.ToUpper(cfg.Method)
+	return cfg, nil
+}
+
+// run faz a requisição descrita por cfg e escreve o corpo em stdout (ou -o)
+func run(ctx context.Context, cfg config, stdin io.Reader, stdout, stderr io.Writer) error {
+	// Mesmo padrão do 4_ctx: o context limita a chamada inteira
+	if cfg.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, cfg.Timeout)
+		defer cancel()
+	}
+
+	body, err := readBody(cfg.Data, stdin)
+	if err != nil {
+		return err
+	}
+
+	var timing *timing
+	if cfg.Verbose {
+		timing, ctx = withTiming(ctx)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, cfg.Method, cfg.URL, body)
+	if err != nil {
+		return err
+	}
+	for name, values := range cfg.Headers {
+		req.Header[name] = values
+	}
+	if cfg.JSON {
+		setDefault(req.Header, "Accept", "application/json")
+		if body != nil {
+			setDefault(req.Header, "Content-Type", "application/json")
+		}
+	}
+
+	resp, err := newClient(cfg, stderr).Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if err := writeBody(cfg, resp, stdout); err != nil {
+		return err
+	}
+	if timing != nil {
+		timing.done()
+		timing.print(stderr)
+	}
+	if cfg.Fail && resp.StatusCode >= 400 {
+		return fmt.Errorf("%w: %s", errHTTPStatus, resp.Status)
+	}
+	return nil
+}
+
+// newClient monta o client do httpClient com a política de redirect das flags
+func newClient(cfg config, stderr io.Writer) *http.Client {
+	opts := httpClient.Options{UserAgent: "6_curl/1.0"}
+	if cfg.Insecure {
+		transport := http.DefaultTransport.(*http.Transport).Clone()
+		transport.TLSClientConfig = &tls.Config{InsecureSkipVerify: true}
+		opts.Transport = transport
+	}
+	if cfg.Verbose {
+		opts.Middlewares = append(opts.Middlewares, dumpHeaders(stderr))
+	}
+
+	client := httpClient.New(opts)
+	client.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+		if !cfg.Follow {
+			return http.ErrUseLastResponse // Devolve o 3xx como veio, como o curl sem -L
+		}
+		if len(via) > cfg.MaxRedirects {
+			return fmt.Errorf("mais de %d redirects", cfg.MaxRedirects)
+		}
+		return nil
+	}
+	return client
+}
+
+// readBody resolve o -d: texto, @arquivo ou @- (stdin). nil sem corpo.
+// O corpo é lido inteiro para o http.NewRequest preencher Content-Length e
+// GetBody (necessário para reenviar em redirects 307/308).
+func readBody(data string, stdin io.Reader) (io.Reader, error) {
+	if data == "" {
+		return nil, nil
+	}
+	name, ok := strings.CutPrefix(data, "@")
+	if !ok {
+		return strings.NewReader(data), nil
+	}
+
+	var content []byte
+	var err error
+	if name == "-" {
+		content, err = io.ReadAll(stdin)
+	} else {
+		content, err = os.ReadFile(name)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("corpo %s: %w", data, err)
+	}
+	return bytes.NewReader(content), nil
+}
+
+// writeBody copia o corpo para a saída; com -json, formata se for JSON válido
+func writeBody(cfg config, resp *http.Response, stdout io.Writer) error {
+	out := stdout
+	if cfg.Output != "" {
+		file, err := os.Create(cfg.Output)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		out = file
+	}
+
+	if !cfg.JSON {
+		_, err := io.Copy(out, resp.Body)
+		return err
+	}
+
+	raw, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	var pretty bytes.Buffer
+	if json.Indent(&pretty, raw, "", "  ") != nil {
+		_, err = out.Write(raw) // Não é JSON: sai como veio
+		return err
+	}
+	pretty.WriteByte('\n')
+	_, err = pretty.WriteTo(out)
+	return err
+}
+
+// setDefault define o header só se ele ainda não existe
+func setDefault(h http.Header, name, value string) {
+	if h.Get(name) == "" {
+		h.Set(name, value)
+	}
+}
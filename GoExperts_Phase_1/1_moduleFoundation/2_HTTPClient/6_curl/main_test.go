@@ -0,0 +1,177 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// echoServer responde com o que recebeu; /redirect manda para /, /slow demora
+func echoServer(t *testing.T, tlsServer bool) *httptest.Server {
+	t.Helper()
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"method":"` + r.Method + `","body":"` + strings.ReplaceAll(string(body), `"`, `'`) +
+			`","accept":"` + r.Header.Get("Accept") + `","type":"` + r.Header.Get("Content-Type") + `","x":"` + r.Header.Get("X-Teste") + `"}`))
+	})
+	mux.HandleFunc("/redirect", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/", http.StatusFound)
+	})
+	mux.HandleFunc("/slow", func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-time.After(time.Second):
+		case <-r.Context().Done():
+		}
+	})
+	mux.HandleFunc("/missing", http.NotFound)
+
+	var server *httptest.Server
+	if tlsServer {
+		server = httptest.NewTLSServer(mux)
+	} else {
+		server = httptest.NewServer(mux)
+	}
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestParseArgs(t *testing.T) {
+	tests := []struct {
+		args   []string
+		method string
+		url    string
+		header string // Valor de X-Teste
+		err    bool
+	}{
+		{[]string{"http://exemplo"}, "GET", "http://exemplo", "", false},
+		{[]string{"-d", "x", "exemplo:8080/ceps"}, "POST", "http://exemplo:8080/ceps", "", false},
+		{[]string{"-X", "put", "-H", "X-Teste: a b", "https://exemplo"}, "PUT", "https://exemplo", "a b", false},
+		{[]string{"-H", "sem-dois-pontos", "http://exemplo"}, "", "", "", true},
+		{[]string{}, "", "", "", true},
+	}
+
+	for _, tt := range tests {
+		cfg, err := parseArgs(tt.args, io.Discard)
+		if (err != nil) != tt.err {
+			t.Errorf("parseArgs(%q) erro = %v; expect erro %v", tt.args, err, tt.err)
+			continue
+		}
+		if err == nil && (cfg.Method != tt.method || cfg.URL != tt.url || cfg.Headers.Get("X-Teste") != tt.header) {
+			t.Errorf("parseArgs(%q) = %s %s X-Teste=%q; expect %s %s %q", tt.args, cfg.Method, cfg.URL, cfg.Headers.Get("X-Teste"), tt.method, tt.url, tt.header)
+		}
+	}
+}
+
+func TestRun(t *testing.T) {
+	server := echoServer(t, false)
+	bodyFile := filepath.Join(t.TempDir(), "corpo.json")
+	os.WriteFile(bodyFile, []byte(`{"cep":"01001000"}`), 0o644)
+
+	tests := []struct {
+		name   string
+		args   []string
+		stdin  string
+		expect string // Trecho esperado na saída
+		err    error  // nil, errHTTPStatus ou context.DeadlineExceeded
+	}{
+		{"GET", []string{server.URL}, "", `"method":"GET","body":""`, nil},
+		{"header", []string{"-H", "X-Teste: 42", server.URL}, "", `"x":"42"`, nil},
+		{"corpo do arquivo", []string{"-d", "@" + bodyFile, server.URL}, "", `"method":"POST","body":"{'cep':'01001000'}"`, nil},
+		{"corpo do stdin", []string{"-X", "PUT", "-d", "@-", server.URL}, "do stdin", `"method":"PUT","body":"do stdin"`, nil},
+		{"json", []string{"-json", "-d", "{}", server.URL}, "", "{\n  \"method\": \"POST\",\n  \"body\": \"{}\",\n  \"accept\": \"application/json\",\n  \"type\": \"application/json\"", nil},
+		{"redirect sem -L", []string{server.URL + "/redirect"}, "", `<a href="/">Found</a>`, nil},
+		{"redirect com -L", []string{"-L", server.URL + "/redirect"}, "", `"method":"GET"`, nil},
+		{"limite de redirects", []string{"-L", "-max-redirs", "0", server.URL + "/redirect"}, "", "", errors.New("redirects")},
+		{"404 sem -fail", []string{server.URL + "/missing"}, "", "404 page not found", nil},
+		{"404 com -fail", []string{"-fail", server.URL + "/missing"}, "", "404 page not found", errHTTPStatus},
+		{"timeout", []string{"-timeout", "50ms", server.URL + "/slow"}, "", "", context.DeadlineExceeded},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg, err := parseArgs(tt.args, io.Discard)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var stdout bytes.Buffer
+			err = run(context.Background(), cfg, strings.NewReader(tt.stdin), &stdout, io.Discard)
+
+			switch {
+			case tt.err == nil && err != nil:
+				t.Fatalf("run() erro inesperado: %v", err)
+			case tt.err != nil && err == nil:
+				t.Fatalf("run() sem erro; expect %v", tt.err)
+			case tt.err != nil && !errors.Is(err, tt.err) && !strings.Contains(err.Error(), tt.err.Error()):
+				t.Fatalf("run() erro = %v; expect %v", err, tt.err)
+			}
+			if !strings.Contains(stdout.String(), tt.expect) {
+				t.Errorf("saída = %q; expect conter %q", stdout.String(), tt.expect)
+			}
+		})
+	}
+}
+
+func TestRunOutputFile(t *testing.T) {
+	server := echoServer(t, false)
+	out := filepath.Join(t.TempDir(), "resposta.json")
+
+	cfg, _ := parseArgs([]string{"-o", out, server.URL}, io.Discard)
+	var stdout bytes.Buffer
+	if err := run(context.Background(), cfg, nil, &stdout, io.Discard); err != nil {
+		t.Fatal(err)
+	}
+	got, _ := os.ReadFile(out)
+	if !strings.Contains(string(got), `"method":"GET"`) || stdout.Len() != 0 {
+		t.Errorf("arquivo = %q, stdout = %q; expect o corpo só no arquivo", got, stdout.String())
+	}
+}
+
+func TestRunVerbose(t *testing.T) {
+	server := echoServer(t, true)
+
+	cfg, _ := parseArgs([]string{"-v", "-k", "-L", "-H", "X-Teste: 1", server.URL + "/redirect"}, io.Discard)
+	var stderr bytes.Buffer
+	if err := run(context.Background(), cfg, nil, io.Discard, &stderr); err != nil {
+		t.Fatal(err)
+	}
+
+	out := stderr.String()
+	for _, expect := range []string{
+		"> GET /redirect HTTP/1.1", "> X-Teste: 1", "> User-Agent: 6_curl/1.0",
+		"< HTTP/1.1 302 Found", "> GET / HTTP/1.1", "< HTTP/1.1 200 OK", "< Content-Type: application/json",
+		"* conexão reaproveitada", "* DNS         -",
+	} {
+		if !strings.Contains(out, strings.TrimPrefix(expect, "* ")) {
+			t.Errorf("verbose sem %q:\n%s", expect, out)
+		}
+	}
+	// O 2º salto reaproveita a conexão: sem conexão nem TLS novos
+	if !strings.Contains(out, "* TLS         -") || !strings.Contains(out, "* 1º byte     ") {
+		t.Errorf("tempos inesperados:\n%s", out)
+	}
+}
+
+func TestRunVerboseNewTLSConnection(t *testing.T) {
+	server := echoServer(t, true)
+
+	cfg, _ := parseArgs([]string{"-v", "-k", server.URL}, io.Discard)
+	var stderr bytes.Buffer
+	if err := run(context.Background(), cfg, nil, io.Discard, &stderr); err != nil {
+		t.Fatal(err)
+	}
+	for _, line := range strings.Split(stderr.String(), "\n") {
+		if (strings.HasPrefix(line, "* conexão ") || strings.HasPrefix(line, "* TLS ")) && strings.HasSuffix(line, " -") {
+			t.Errorf("fase sem tempo numa conexão nova: %q", line)
+		}
+	}
+}
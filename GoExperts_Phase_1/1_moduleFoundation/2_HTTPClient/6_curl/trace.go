@@ -0,0 +1,139 @@
+package main
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptrace"
+	"slices"
+	"sync"
+	"time"
+
+	"GoProject/1_moduleFoundation/2_HTTPClient/httpClient"
+)
+
+// timing guarda os instantes de cada fase da requisição (httptrace).
+// Com redirects, cada salto recomeça em GetConn: o resumo é o do último.
+type timing struct {
+	mu        sync.Mutex
+	start     time.Time
+	hop       time.Time // Início do salto atual
+	dnsStart  time.Time
+	dnsDone   time.Time
+	connStart time.Time
+	connDone  time.Time
+	tlsStart  time.Time
+	tlsDone   time.Time
+	firstByte time.Time
+	end       time.Time
+	reused    bool
+	addr      string
+}
+
+// withTiming devolve o timing e um ctx que alimenta ele pelo httptrace
+func withTiming(ctx context.Context) (*timing, context.Context) {
+	t := &timing{start: time.Now()}
+	set := func(field *time.Time) {
+		t.mu.Lock()
+		defer t.mu.Unlock()
+		*field = time.Now()
+	}
+
+	trace := &httptrace.ClientTrace{
+		GetConn: func(string) {
+			t.mu.Lock()
+			defer t.mu.Unlock()
+			t.hop = time.Now()
+			t.dnsStart, t.dnsDone, t.connStart, t.connDone = time.Time{}, time.Time{}, time.Time{}, time.Time{}
+			t.tlsStart, t.tlsDone, t.firstByte = time.Time{}, time.Time{}, time.Time{}
+		},
+		GotConn: func(info httptrace.GotConnInfo) {
+			t.mu.Lock()
+			defer t.mu.Unlock()
+			t.reused = info.Reused
+			t.addr = info.Conn.RemoteAddr().String()
+		},
+		DNSStart:             func(httptrace.DNSStartInfo) { set(&t.dnsStart) },
+		DNSDone:              func(httptrace.DNSDoneInfo) { set(&t.dnsDone) },
+		ConnectStart:         func(string, string) { set(&t.connStart) },
+		ConnectDone:          func(string, string, error) { set(&t.connDone) },
+		TLSHandshakeStart:    func() { set(&t.tlsStart) },
+		TLSHandshakeDone:     func(tls.ConnectionState, error) { set(&t.tlsDone) },
+		GotFirstResponseByte: func() { set(&t.firstByte) },
+	}
+	return t, httptrace.WithClientTrace(ctx, trace)
+}
+
+// done marca o fim da leitura do corpo
+func (t *timing) done() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.end = time.Now()
+}
+
+// print escreve o resumo das fases; "-" para as que não aconteceram
+// (ex.: DNS com IP na URL, TLS em http://, tudo com conexão reaproveitada)
+func (t *timing) print(w io.Writer) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	phase := func(from, to time.Time) string {
+		if from.IsZero() || to.IsZero() {
+			return "-"
+		}
+		return to.Sub(from).Round(10 * time.Microsecond).String()
+	}
+
+	fmt.Fprintf(w, "* conectado a %s", t.addr)
+	if t.reused {
+		fmt.Fprint(w, " (conexão reaproveitada)")
+	}
+	fmt.Fprintln(w)
+	fmt.Fprintf(w, "* DNS         %s\n", phase(t.dnsStart, t.dnsDone))
+	fmt.Fprintf(w, "* conexão     %s\n", phase(t.connStart, t.connDone))
+	fmt.Fprintf(w, "* TLS         %s\n", phase(t.tlsStart, t.tlsDone))
+	fmt.Fprintf(w, "* 1º byte     %s\n", phase(t.hop, t.firstByte))
+	fmt.Fprintf(w, "* total       %s\n", phase(t.start, t.end))
+}
+
+// dumpHeaders escreve, como o curl -v, os headers enviados (>) e recebidos (<)
+// de cada salto. Fica no fim da cadeia: vê o User-Agent e o X-Request-ID já definidos.
+func dumpHeaders(w io.Writer) httpClient.Middleware {
+	return func(next http.RoundTripper) http.RoundTripper {
+		return httpClient.RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			proto := req.Proto
+			if proto == "" {
+				proto = "HTTP/1.1" // Requisições de redirect vêm sem Proto
+			}
+			fmt.Fprintf(w, "> %s %s %s\n", req.Method, req.URL.RequestURI(), proto)
+			fmt.Fprintf(w, "> Host: %s\n", req.URL.Host)
+			writeHeaders(w, ">", req.Header)
+			fmt.Fprintln(w, ">")
+
+			resp, err := next.RoundTrip(req)
+			if err != nil {
+				return nil, err
+			}
+			fmt.Fprintf(w, "< %s %s\n", resp.Proto, resp.Status)
+			writeHeaders(w, "<", resp.Header)
+			fmt.Fprintln(w, "<")
+			return resp, nil
+		})
+	}
+}
+
+// writeHeaders escreve os headers em ordem alfabética
+func writeHeaders(w io.Writer, prefix string, h http.Header) {
+	names := make([]string, 0, len(h))
+	for name := range h {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	for _, name := range names {
+		for _, value := range h[name] {
+			fmt.Fprintf(w, "%s %s: %s\n", prefix, name, value)
+		}
+	}
+}
@@ -0,0 +1,305 @@
+// Package cassette grava requisições HTTP reais em um arquivo ("cassete") e
+// depois as reproduz, para testar código que chama APIs externas sem rede.
+//
+//	rec, _ := cassette.New("testdata/viacep.json", cassette.Options{})
+//	client := &http.Client{Transport: rec}
+//
+// Por padrão o Recorder só reproduz (ModeReplay). Para gravar de novo contra
+// os servidores reais: CASSETTE_MODE=record go test ./...
+// Nos testes, cassettetest.New já salva e confere o cassete no fim do teste.
+package cassette
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+// ModeEnv é a variável de ambiente lida quando Options.Mode não é informado
+const ModeEnv = "CASSETTE_MODE"
+
+// Mode define se o Recorder chama a rede ou só reproduz o cassete
+type Mode int
+
+const (
+	ModeReplay Mode = iota + 1 // Só responde a partir do cassete; requisição sem gravação é erro
+	ModeRecord                 // Chama o servidor real e grava tudo (o cassete é reescrito no Save)
+)
+
+// ErrNoMatch é devolvido em ModeReplay quando a requisição não está no cassete
+var ErrNoMatch = errors.New("cassette: requisição não gravada")
+
+// Redacted substitui o valor dos cabeçalhos sensíveis no arquivo
+const Redacted = "[REDACTED]"
+
+// defaultRedact são os cabeçalhos que nunca vão para o arquivo
+var defaultRedact = []string{"Authorization", "Proxy-Authorization", "Cookie", "Set-Cookie", "X-Api-Key"}
+
+// Options configura o Recorder. Campos zerados usam os valores padrão.
+type Options struct {
+	Mode          Mode              // Padrão: $CASSETTE_MODE ("record" ou "replay"), senão ModeReplay
+	Transport     http.RoundTripper // Usado para gravar (padrão http.DefaultTransport)
+	RedactHeaders []string          // Cabeçalhos extras a esconder (além de Authorization, Cookie, ...)
+}
+
+// Cassette é o conteúdo do arquivo JSON
+type Cassette struct {
+	Version      int           `json:"version"`
+	Comment      string        `json:"comment,omitempty"` // Origem quando o cassete não foi gravado (ex.: sintético); regravar descarta
+	Interactions []Interaction `json:"interactions"`
+}
+
+// Interaction é um par requisição/resposta gravado
+type Interaction struct {
+	Request  Request  `json:"request"`
+	Response Response `json:"response"`
+}
+
+// Request é a parte da requisição usada para encontrar a gravação (método, URL e corpo)
+type Request struct {
+	Method  string      `json:"method"`
+	URL     string      `json:"url"`
+	Headers http.Header `json:"headers,omitempty"`
+	Body    Body        `json:"body,omitempty"`
+}
+
+// Response é a resposta devolvida no replay
+type Response struct {
+	Status  int         `json:"status"`
+	Headers http.Header `json:"headers,omitempty"`
+	Body    Body        `json:"body,omitempty"`
+}
+
+// Body é gravado como texto quando é UTF-8 válido e em base64 caso contrário
+type Body []byte
+
+func (b Body) MarshalJSON() ([]byte, error) {
+	if utf8.Valid(b) {
+		return json.Marshal(string(b))
+	}
+	return json.Marshal(map[string]string{"base64": base64.StdEncoding.EncodeToString(b)})
+}
+
+func (b *Body) UnmarshalJSON(data []byte) error {
+	var text string
+	if err := json.Unmarshal(data, &text); err == nil {
+		*b = Body(text)
+		return nil
+	}
+	var encoded struct {
+		Base64 string `json:"base64"`
+	}
+	if err := json.Unmarshal(data, &encoded); err != nil {
+		return err
+	}
+	decoded, err := base64.StdEncoding.DecodeString(encoded.Base64)
+	*b = decoded
+	return err
+}
+
+// Recorder é um http.RoundTripper que grava ou reproduz interações.
+// É seguro para uso concorrente.
+type Recorder struct {
+	path      string
+	mode      Mode
+	transport http.RoundTripper
+	redact    []string
+
+	mu        sync.Mutex
+	cassette  Cassette
+	used      []bool   // Interações já reproduzidas (a mesma requisição repetida avança na fila)
+	unmatched []string // Requisições sem gravação, para o relatório do teste
+}
+
+// New abre o cassete em path. Em ModeReplay o arquivo precisa existir.
+func New(path string, opts Options) (*Recorder, error) {
+	if opts.Mode == 0 {
+		opts.Mode = modeFromEnv()
+	}
+	if opts.Transport == nil {
+		opts.Transport = http.DefaultTransport
+	}
+
+	r := &Recorder{
+		path:      path,
+		mode:      opts.Mode,
+		transport: opts.Transport,
+		redact:    append(append([]string{}, defaultRedact...), opts.RedactHeaders...),
+		cassette:  Cassette{Version: 1},
+	}
+	if r.mode == ModeRecord {
+		return r, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("cassette: %w (grave com %s=record)", err, ModeEnv)
+	}
+	if err := json.Unmarshal(data, &r.cassette); err != nil {
+		return nil, fmt.Errorf("cassette: %s inválido: %w", path, err)
+	}
+	r.used = make([]bool, len(r.cassette.Interactions))
+	return r, nil
+}
+
+func modeFromEnv() Mode {
+	if strings.EqualFold(os.Getenv(ModeEnv), "record") {
+		return ModeRecord
+	}
+	return ModeReplay
+}
+
+// Mode devolve o modo em uso
+func (r *Recorder) Mode() Mode { return r.mode }
+
+// RoundTrip implementa http.RoundTripper
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	if err := req.Context().Err(); err != nil {
+		return nil, err
+	}
+	body, err := readRequestBody(req)
+	if err != nil {
+		return nil, err
+	}
+	if r.mode == ModeRecord {
+		return r.record(req, body)
+	}
+	return r.replay(req, body)
+}
+
+// replay procura a primeira interação ainda não usada com mesmo método, URL e corpo.
+// Se todas já foram usadas, repete a última (ex.: a mesma busca feita duas vezes).
+func (r *Recorder) replay(req *http.Request, body []byte) (*http.Response, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	found := -1
+	for i, in := range r.cassette.Interactions {
+		if !matches(in.Request, req, body) {
+			continue
+		}
+		found = i
+		if !r.used[i] {
+			break
+		}
+	}
+	if found < 0 {
+		r.unmatched = append(r.unmatched, req.Method+" "+req.URL.String())
+		return nil, fmt.Errorf("%w: %s %s em %s", ErrNoMatch, req.Method, req.URL, r.path)
+	}
+	r.used[found] = true
+
+	recorded := r.cassette.Interactions[found].Response
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", recorded.Status, http.StatusText(recorded.Status)),
+		StatusCode:    recorded.Status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        recorded.Headers.Clone(),
+		Body:          io.NopCloser(bytes.NewReader(recorded.Body)),
+		ContentLength: int64(len(recorded.Body)),
+		Request:       req,
+	}, nil
+}
+
+// record chama o servidor real, guarda a interação e devolve uma cópia da resposta
+func (r *Recorder) record(req *http.Request, body []byte) (*http.Response, error) {
+	out := req.Clone(req.Context())
+	if body != nil {
+		out.Body = io.NopCloser(bytes.NewReader(body))
+	}
+	resp, err := r.transport.RoundTrip(out)
+	if err != nil {
+		return nil, err // Erros de rede não são gravados
+	}
+	respBody, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(respBody))
+
+	in := Interaction{
+		Request: Request{
+			Method:  req.Method,
+			URL:     req.URL.String(),
+			Headers: r.redactHeaders(req.Header),
+			Body:    body,
+		},
+		Response: Response{
+			Status:  resp.StatusCode,
+			Headers: r.redactHeaders(resp.Header),
+			Body:    respBody,
+		},
+	}
+
+	r.mu.Lock()
+	r.cassette.Interactions = append(r.cassette.Interactions, in)
+	r.mu.Unlock()
+	return resp, nil
+}
+
+// Save grava o cassete em disco (só em ModeRecord; em ModeReplay não faz nada)
+func (r *Recorder) Save() error {
+	if r.mode != ModeRecord {
+		return nil
+	}
+
+	r.mu.Lock()
+	data, err := json.MarshalIndent(r.cassette, "", "  ")
+	r.mu.Unlock()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(r.path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(r.path, append(data, '\n'), 0o644)
+}
+
+// Unmatched lista as requisições que não tinham gravação (ModeReplay)
+func (r *Recorder) Unmatched() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]string{}, r.unmatched...)
+}
+
+// matches compara método, URL e corpo
+func matches(recorded Request, req *http.Request, body []byte) bool {
+	return recorded.Method == req.Method &&
+		recorded.URL == req.URL.String() &&
+		bytes.Equal(recorded.Body, body)
+}
+
+// redactHeaders copia os cabeçalhos escondendo os sensíveis
+func (r *Recorder) redactHeaders(h http.Header) http.Header {
+	if len(h) == 0 {
+		return nil
+	}
+	clone := h.Clone()
+	for _, name := range r.redact {
+		if _, ok := clone[http.CanonicalHeaderKey(name)]; ok {
+			clone.Set(name, Redacted)
+		}
+	}
+	return clone
+}
+
+// readRequestBody lê e fecha o corpo (em ModeRecord ele é reenviado em uma cópia da requisição)
+func readRequestBody(req *http.Request) ([]byte, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil, nil
+	}
+	defer req.Body.Close()
+	return io.ReadAll(req.Body)
+}
@@ -0,0 +1,168 @@
+package cassette
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+// echoServer responde com método, caminho, corpo e um contador de chamadas
+func echoServer(t *testing.T) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := calls.Add(1)
+		body, _ := io.ReadAll(r.Body)
+		w.Header().Set("Set-Cookie", "session=segredo")
+		w.Header().Set("X-Call", fmt.Sprint(n))
+		fmt.Fprintf(w, "%s %s %s #%d", r.Method, r.URL.Path, body, n)
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &calls
+}
+
+func get(t *testing.T, client *http.Client, method, url, body string) (string, error) {
+	t.Helper()
+	req, err := http.NewRequest(method, url, strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Authorization", "Bearer token-secreto")
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	return string(data), err
+}
+
+func TestRecordAndReplay(t *testing.T) {
+	srv, calls := echoServer(t)
+	path := filepath.Join(t.TempDir(), "echo.json")
+
+	// Grava
+	rec, err := New(path, Options{Mode: ModeRecord})
+	if err != nil {
+		t.Fatal(err)
+	}
+	client := &http.Client{Transport: rec}
+	recorded := []string{}
+	for _, body := range []string{"a", "b", "a"} {
+		got, err := get(t, client, http.MethodPost, srv.URL+"/echo", body)
+		if err != nil {
+			t.Fatalf("gravação: erro inesperado %v", err)
+		}
+		recorded = append(recorded, got)
+	}
+	if err := rec.Save(); err != nil {
+		t.Fatalf("Save() erro inesperado: %v", err)
+	}
+
+	// Reproduz sem chamar o servidor
+	srv.Close()
+	rec, err = New(path, Options{Mode: ModeReplay})
+	if err != nil {
+		t.Fatal(err)
+	}
+	client = &http.Client{Transport: rec}
+
+	tests := []struct {
+		body   string
+		expect string
+	}{
+		{"a", recorded[0]},
+		{"b", recorded[1]},
+		{"a", recorded[2]}, // Mesma requisição: avança para a próxima gravação
+		{"a", recorded[2]}, // Acabaram: repete a última
+	}
+	for _, tt := range tests {
+		got, err := get(t, client, http.MethodPost, srv.URL+"/echo", tt.body)
+		if err != nil {
+			t.Fatalf("replay(%q): erro inesperado %v", tt.body, err)
+		}
+		if got != tt.expect {
+			t.Errorf("replay(%q) = %q; expect %q", tt.body, got, tt.expect)
+		}
+	}
+	if calls.Load() != 3 {
+		t.Errorf("chamadas ao servidor = %d; expect 3 (só na gravação)", calls.Load())
+	}
+}
+
+func TestRedactHeaders(t *testing.T) {
+	srv, _ := echoServer(t)
+	path := filepath.Join(t.TempDir(), "redact.json")
+
+	rec, _ := New(path, Options{Mode: ModeRecord, RedactHeaders: []string{"x-call"}})
+	if _, err := get(t, &http.Client{Transport: rec}, http.MethodGet, srv.URL+"/", ""); err != nil {
+		t.Fatal(err)
+	}
+	rec.Save()
+
+	data, _ := os.ReadFile(path)
+	for _, secret := range []string{"token-secreto", "session=segredo", `"1"`} {
+		if strings.Contains(string(data), secret) {
+			t.Errorf("cassete contém %q:\n%s", secret, data)
+		}
+	}
+	if !strings.Contains(string(data), Redacted) {
+		t.Errorf("cassete sem %q:\n%s", Redacted, data)
+	}
+}
+
+func TestReplayUnmatched(t *testing.T) {
+	srv, _ := echoServer(t)
+	path := filepath.Join(t.TempDir(), "unmatched.json")
+
+	rec, _ := New(path, Options{Mode: ModeRecord})
+	get(t, &http.Client{Transport: rec}, http.MethodPost, srv.URL+"/echo", "a")
+	rec.Save()
+
+	rec, _ = New(path, Options{Mode: ModeReplay})
+	client := &http.Client{Transport: rec}
+
+	tests := []struct {
+		name, method, path, body string
+	}{
+		{"outro método", http.MethodPut, "/echo", "a"},
+		{"outra URL", http.MethodPost, "/echo?x=1", "a"},
+		{"outro corpo", http.MethodPost, "/echo", "b"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := get(t, client, tt.method, srv.URL+tt.path, tt.body); !errors.Is(err, ErrNoMatch) {
+				t.Errorf("erro = %v; expect %v", err, ErrNoMatch)
+			}
+		})
+	}
+	if got := len(rec.Unmatched()); got != len(tests) {
+		t.Errorf("Unmatched() = %d itens; expect %d", got, len(tests))
+	}
+}
+
+func TestReplayMissingFile(t *testing.T) {
+	_, err := New(filepath.Join(t.TempDir(), "nao-existe.json"), Options{Mode: ModeReplay})
+	if !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("New() erro = %v; expect %v", err, os.ErrNotExist)
+	}
+}
+
+func TestBinaryBody(t *testing.T) {
+	binary := Body{0xff, 0x00, 0xfe}
+	data, err := binary.MarshalJSON()
+	if err != nil || !strings.Contains(string(data), "base64") {
+		t.Fatalf("MarshalJSON() = %s, %v; expect base64", data, err)
+	}
+	var decoded Body
+	if err := decoded.UnmarshalJSON(data); err != nil || string(decoded) != string(binary) {
+		t.Errorf("UnmarshalJSON() = %v, %v; expect %v", decoded, err, binary)
+	}
+}
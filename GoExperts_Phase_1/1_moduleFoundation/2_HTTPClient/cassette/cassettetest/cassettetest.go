@@ -0,0 +1,48 @@
+// Package cassettetest tem os helpers de teste do cassette. Fica separado para
+// que o pacote cassette não importe testing (e as flags dele) nos binários.
+package cassettetest
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"GoProject/1_moduleFoundation/2_HTTPClient/cassette"
+)
+
+// New abre o cassete para um teste e registra no t.Cleanup:
+//   - ModeRecord: salva o arquivo no final
+//   - ModeReplay: reprova o teste se alguma requisição não estava gravada
+//     (o erro do RoundTrip pode ter sido tratado/escondido pelo código testado)
+func New(t testing.TB, path string, opts cassette.Options) *cassette.Recorder {
+	t.Helper()
+	rec, err := cassette.New(path, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		if err := rec.Save(); err != nil {
+			t.Errorf("cassette: erro ao salvar %s: %v", path, err)
+		}
+		if unmatched := rec.Unmatched(); len(unmatched) > 0 {
+			t.Errorf("cassette: requisições sem gravação em %s:\n  %s\n(grave com %s=record)",
+				path, strings.Join(unmatched, "\n  "), cassette.ModeEnv)
+		}
+	})
+	return rec
+}
+
+// DefaultTransport troca o http.DefaultTransport pelo cassete até o fim do
+// teste, para testar código que usa http.Get ou um http.Client sem Transport.
+// A gravação (ModeRecord) sai pelo transport original.
+func DefaultTransport(t testing.TB, path string, opts cassette.Options) *cassette.Recorder {
+	t.Helper()
+	original := http.DefaultTransport
+	if opts.Transport == nil {
+		opts.Transport = original
+	}
+	rec := New(t, path, opts)
+	http.DefaultTransport = rec
+	t.Cleanup(func() { http.DefaultTransport = original })
+	return rec
+}
@@ -0,0 +1,65 @@
+package cassettetest
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"GoProject/1_moduleFoundation/2_HTTPClient/cassette"
+)
+
+func TestNew(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, "gravado")
+	}))
+	path := filepath.Join(t.TempDir(), "ola.json")
+
+	get := func(t *testing.T, rec *cassette.Recorder) string {
+		resp, err := (&http.Client{Transport: rec}).Get(srv.URL + "/ola")
+		if err != nil {
+			t.Fatalf("Get() erro inesperado: %v", err)
+		}
+		defer resp.Body.Close()
+		body, _ := io.ReadAll(resp.Body)
+		return string(body)
+	}
+
+	// O Cleanup do subteste grava o arquivo
+	t.Run("record", func(t *testing.T) {
+		get(t, New(t, path, cassette.Options{Mode: cassette.ModeRecord}))
+	})
+
+	srv.Close()
+	t.Run("replay", func(t *testing.T) {
+		if got := get(t, New(t, path, cassette.Options{Mode: cassette.ModeReplay})); got != "gravado" {
+			t.Errorf("replay = %q; expect gravado", got)
+		}
+	})
+}
+
+func TestDefaultTransport(t *testing.T) {
+	original := http.DefaultTransport
+	path := filepath.Join(t.TempDir(), "exemplo.json")
+	os.WriteFile(path, []byte(`{"version": 1, "interactions": [{"request": {"method": "GET", "url": "http://exemplo.invalid/"}, "response": {"status": 204}}]}`), 0o644)
+
+	t.Run("troca", func(t *testing.T) {
+		rec := DefaultTransport(t, path, cassette.Options{Mode: cassette.ModeReplay})
+		if http.DefaultTransport != http.RoundTripper(rec) {
+			t.Fatal("http.DefaultTransport não foi trocado")
+		}
+		resp, err := http.Get("http://exemplo.invalid/")
+		if err != nil {
+			t.Fatalf("Get() erro inesperado: %v", err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusNoContent {
+			t.Errorf("status = %d; expect 204", resp.StatusCode)
+		}
+	})
+	if http.DefaultTransport != original {
+		t.Error("http.DefaultTransport não foi restaurado no fim do teste")
+	}
+}
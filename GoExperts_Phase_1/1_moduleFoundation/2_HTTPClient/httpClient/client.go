@@ -0,0 +1,109 @@
+// Package httpClient monta *http.Client a partir de opções: timeouts, retry,
+// headers padrão, User-Agent e uma cadeia de middlewares de RoundTripper
+// (logging, métricas, request ID, ...).
+//
+//	metrics := &httpClient.Metrics{}
+//	client := httpClient.New(httpClient.Options{
+//		Timeout:     10 * time.Second,
+//		UserAgent:   "meu-app/1.0",
+//		Retry:       httpClient.RetryPolicy{MaxAttempts: 3, BaseDelay: 200 * time.Millisecond},
+//		Middlewares: []httpClient.Middleware{httpClient.Logging(nil), httpClient.Measure(metrics)},
+//	})
+package httpClient
+
+import (
+	"net"
+	"net/http"
+	"time"
+)
+
+// Middleware embrulha um RoundTripper com algum comportamento extra
+type Middleware func(next http.RoundTripper) http.RoundTripper
+
+// RoundTripperFunc transforma uma função em http.RoundTripper (como o http.HandlerFunc)
+type RoundTripperFunc func(req *http.Request) (*http.Response, error)
+
+func (f RoundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// Chain aplica os middlewares sobre base; o primeiro da lista é o mais externo
+// (vê a requisição primeiro e a resposta por último)
+func Chain(base http.RoundTripper, middlewares ...Middleware) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		base = middlewares[i](base)
+	}
+	return base
+}
+
+// Options configura o client. Campos zerados ficam sem limite / desligados.
+type Options struct {
+	// Timeout é o limite total da chamada, com retries e leitura do corpo (http.Client.Timeout)
+	Timeout time.Duration
+
+	// Timeouts de cada conexão; só valem quando Transport é nil
+	DialTimeout           time.Duration // Padrão 5s
+	TLSHandshakeTimeout   time.Duration // Padrão 5s
+	ResponseHeaderTimeout time.Duration // Espera pelos headers da resposta (padrão sem limite)
+
+	Retry     RetryPolicy // MaxAttempts <= 1 ==> sem retry
+	Hedge     HedgePolicy // Delay <= 0 ==> sem hedging
+	UserAgent string      // Enviado quando a requisição não define o seu
+	Headers   http.Header // Headers padrão, enviados quando a requisição não define os seus
+
+	// Middlewares ficam entre o retry/hedge e a rede: cada tentativa e cada cópia passam por eles
+	Middlewares []Middleware
+
+	// Transport é o RoundTripper final (padrão: cópia do http.DefaultTransport com os timeouts acima)
+	Transport http.RoundTripper
+}
+
+// New monta o client. A ordem da cadeia é:
+//
+//	headers/User-Agent ==> request ID ==> retry ==> hedge ==> Options.Middlewares ==> Transport
+//
+// Assim todas as tentativas (e cópias) da mesma chamada levam o mesmo X-Request-ID.
+func New(opts Options) *http.Client {
+	base := opts.Transport
+	if base == nil {
+		base = newTransport(opts)
+	}
+
+	headers := opts.Headers.Clone()
+	if opts.UserAgent != "" {
+		if headers == nil {
+			headers = http.Header{}
+		}
+		headers.Set("User-Agent", opts.UserAgent)
+	}
+
+	middlewares := []Middleware{DefaultHeaders(headers), RequestID()}
+	if opts.Retry.MaxAttempts > 1 {
+		middlewares = append(middlewares, Retry(opts.Retry))
+	}
+	if opts.Hedge.Delay > 0 {
+		middlewares = append(middlewares, Hedge(opts.Hedge))
+	}
+	middlewares = append(middlewares, opts.Middlewares...)
+
+	return &http.Client{Timeout: opts.Timeout, Transport: Chain(base, middlewares...)}
+}
+
+func newTransport(opts Options) *http.Transport {
+	dial, tls := opts.DialTimeout, opts.TLSHandshakeTimeout
+	if dial <= 0 {
+		dial = 5 * time.Second
+	}
+	if tls <= 0 {
+		tls = 5 * time.Second
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.DialContext = (&net.Dialer{Timeout: dial, KeepAlive: 30 * time.Second}).DialContext
+	transport.TLSHandshakeTimeout = tls
+	transport.ResponseHeaderTimeout = opts.ResponseHeaderTimeout
+	return transport
+}
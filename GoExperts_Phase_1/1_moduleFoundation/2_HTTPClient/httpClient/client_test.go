@@ -0,0 +1,129 @@
+package httpClient
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestChainOrder(t *testing.T) {
+	var calls []string
+	mark := func(name string) Middleware {
+		return func(next http.RoundTripper) http.RoundTripper {
+			return RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+				calls = append(calls, name+" antes")
+				resp, err := next.RoundTrip(req)
+				calls = append(calls, name+" depois")
+				return resp, err
+			})
+		}
+	}
+	base := RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		calls = append(calls, "rede")
+		return &http.Response{StatusCode: http.StatusOK, Body: http.NoBody}, nil
+	})
+
+	rt := Chain(base, mark("a"), mark("b"))
+	if _, err := rt.RoundTrip(httptest.NewRequest(http.MethodGet, "http://exemplo", nil)); err != nil {
+		t.Fatal(err)
+	}
+
+	expect := []string{"a antes", "b antes", "rede", "b depois", "a depois"}
+	if !slices.Equal(calls, expect) {
+		t.Errorf("ordem = %v; expect %v", calls, expect)
+	}
+}
+
+func TestNewHeaders(t *testing.T) {
+	var got http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r.Header.Clone()
+	}))
+	defer server.Close()
+
+	client := New(Options{
+		UserAgent: "teste/1.0",
+		Headers:   http.Header{"Accept": {"application/json"}},
+	})
+
+	req, _ := http.NewRequest(http.MethodGet, server.URL, nil)
+	req.Header.Set("Accept", "text/plain") // O da requisição vale mais que o padrão
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+
+	if got.Get("User-Agent") != "teste/1.0" {
+		t.Errorf("User-Agent = %q; expect teste/1.0", got.Get("User-Agent"))
+	}
+	if got.Get("Accept") != "text/plain" {
+		t.Errorf("Accept = %q; expect text/plain", got.Get("Accept"))
+	}
+	if len(got.Get(RequestIDHeader)) != 16 {
+		t.Errorf("%s = %q; expect ID de 16 caracteres", RequestIDHeader, got.Get(RequestIDHeader))
+	}
+	if req.Header.Get(RequestIDHeader) != "" {
+		t.Errorf("a requisição original foi alterada: %v", req.Header)
+	}
+}
+
+func TestNewTimeout(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-time.After(time.Second):
+		case <-r.Context().Done():
+		}
+	}))
+	defer server.Close()
+
+	client := New(Options{Timeout: 50 * time.Millisecond})
+	start := time.Now()
+	_, err := client.Get(server.URL)
+
+	var timeout interface{ Timeout() bool }
+	if !errors.As(err, &timeout) || !timeout.Timeout() {
+		t.Fatalf("Get() erro = %v; expect timeout", err)
+	}
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("Get() levou %v; expect ~50ms", elapsed)
+	}
+}
+
+func TestNewMiddlewaresSeeEachAttempt(t *testing.T) {
+	attempts := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		if attempts < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer server.Close()
+
+	var ids []string
+	record := func(next http.RoundTripper) http.RoundTripper {
+		return RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			ids = append(ids, req.Header.Get(RequestIDHeader))
+			return next.RoundTrip(req)
+		})
+	}
+	client := New(Options{
+		Retry:       RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond},
+		Middlewares: []Middleware{record},
+	})
+
+	resp, err := client.Get(server.URL)
+	if err != nil || resp.StatusCode != http.StatusOK {
+		t.Fatalf("Get() = %v, %v; expect 200", resp, err)
+	}
+	resp.Body.Close()
+
+	// 3 tentativas, todas com o mesmo request ID
+	if len(ids) != 3 || ids[0] == "" || strings.Count(strings.Join(ids, ","), ids[0]) != 3 {
+		t.Errorf("request IDs por tentativa = %v; expect 3 iguais", ids)
+	}
+}
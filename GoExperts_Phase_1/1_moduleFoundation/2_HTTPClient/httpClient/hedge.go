@@ -0,0 +1,219 @@
+package httpClient
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// Hedging: se a resposta demora mais que Delay, uma cópia da requisição é
+// disparada e vale a primeira que responder; a outra é cancelada pelo ctx.
+// Corta a cauda de latência (p99) de upstreams que às vezes ficam lentos,
+// ao custo de requisições extras, que o Budget limita.
+
+// hedgeBurst é o máximo de fichas guardadas pelo Budget (cópias seguidas
+// permitidas depois de um período calmo)
+const hedgeBurst = 10
+
+// HedgePolicy configura o Hedge. Delay <= 0 desliga.
+type HedgePolicy struct {
+	Delay     time.Duration // Espera pela resposta antes de disparar uma cópia (ex.: a latência p95 do upstream)
+	MaxHedges int           // Cópias extras por requisição (padrão 1)
+	Budget    float64       // Cópias por requisição no longo prazo (padrão 0.1 = até 10% de carga extra)
+	Stats     *HedgeStats   // Opcional: contadores de cópias e vitórias
+}
+
+// HedgeStats conta as cópias disparadas pelo Hedge
+type HedgeStats struct {
+	mu       sync.Mutex
+	requests int64
+	hedges   int64
+	wins     int64
+	denied   int64
+}
+
+// HedgeSnapshot é uma cópia dos contadores, pronta para virar JSON
+type HedgeSnapshot struct {
+	Requests int64   `json:"requests"` // Requisições que podiam ter cópia (idempotentes)
+	Hedges   int64   `json:"hedges"`   // Cópias disparadas
+	Wins     int64   `json:"wins"`     // Vezes em que uma cópia respondeu primeiro
+	Denied   int64   `json:"denied"`   // Cópias não disparadas por falta de Budget
+	WinRate  float64 `json:"win_rate"` // Wins / Hedges
+}
+
+func (s *HedgeStats) add(requests, hedges, wins, denied int64) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.requests += requests
+	s.hedges += hedges
+	s.wins += wins
+	s.denied += denied
+}
+
+// Snapshot devolve os contadores atuais
+func (s *HedgeStats) Snapshot() HedgeSnapshot {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	snap := HedgeSnapshot{Requests: s.requests, Hedges: s.hedges, Wins: s.wins, Denied: s.denied}
+	if s.hedges > 0 {
+		snap.WinRate = float64(s.wins) / float64(s.hedges)
+	}
+	return snap
+}
+
+// Hedge dispara cópias das requisições idempotentes (as mesmas que o Retry
+// repete) que não respondem em policy.Delay. A primeira resposta vence, mesmo
+// que seja um erro HTTP; falha de rede só é devolvida se todas falharem.
+func Hedge(policy HedgePolicy) Middleware {
+	if policy.MaxHedges <= 0 {
+		policy.MaxHedges = 1
+	}
+	if policy.Budget <= 0 {
+		policy.Budget = 0.1
+	}
+	return func(next http.RoundTripper) http.RoundTripper {
+		if policy.Delay <= 0 {
+			return next
+		}
+		return &hedger{next: next, policy: policy, tokens: hedgeBurst}
+	}
+}
+
+type hedger struct {
+	next   http.RoundTripper
+	policy HedgePolicy
+
+	mu     sync.Mutex
+	tokens float64 // Cada requisição deposita Budget; cada cópia gasta 1
+}
+
+type hedgeResult struct {
+	attempt int
+	resp    *http.Response
+	err     error
+}
+
+func (h *hedger) RoundTrip(req *http.Request) (*http.Response, error) {
+	if !retryable(req) {
+		return h.next.RoundTrip(req)
+	}
+	h.deposit()
+
+	results := make(chan hedgeResult, h.policy.MaxHedges+1)
+	var cancels []context.CancelFunc
+	launch := func() error {
+		attempt := len(cancels)
+		ctx, cancel := context.WithCancel(req.Context())
+		try := req.WithContext(ctx)
+		if attempt > 0 && req.Body != nil && req.Body != http.NoBody {
+			body, err := req.GetBody()
+			if err != nil {
+				cancel()
+				return err
+			}
+			try.Body = body
+		}
+		cancels = append(cancels, cancel)
+		go func() {
+			resp, err := h.next.RoundTrip(try)
+			results <- hedgeResult{attempt, resp, err}
+		}()
+		return nil
+	}
+
+	launch()
+	pending, hedges, denied := 1, int64(0), int64(0)
+	timer := time.NewTimer(h.policy.Delay)
+	defer timer.Stop()
+
+	for {
+		select {
+		case r := <-results:
+			pending--
+			if r.err != nil {
+				cancels[r.attempt]()
+				if pending > 0 {
+					continue // Ainda há uma tentativa em andamento
+				}
+				h.policy.Stats.add(1, hedges, 0, denied)
+				return nil, r.err
+			}
+
+			// Vencedora: cancela as outras e descarta as respostas que ainda chegarem
+			for i, cancel := range cancels {
+				if i != r.attempt {
+					cancel()
+				}
+			}
+			go discard(results, pending)
+
+			wins := int64(0)
+			if r.attempt > 0 {
+				wins = 1
+			}
+			h.policy.Stats.add(1, hedges, wins, denied)
+			r.resp.Body = &cancelBody{ReadCloser: r.resp.Body, cancel: cancels[r.attempt]}
+			return r.resp, nil
+
+		case <-timer.C:
+			if len(cancels) > h.policy.MaxHedges {
+				continue
+			}
+			if !h.withdraw() {
+				denied++
+				continue
+			}
+			if launch() == nil {
+				pending++
+				hedges++
+				timer.Reset(h.policy.Delay)
+			}
+		}
+	}
+}
+
+// deposit credita o Budget de uma requisição, até hedgeBurst
+func (h *hedger) deposit() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.tokens = min(h.tokens+h.policy.Budget, hedgeBurst)
+}
+
+// withdraw gasta uma ficha; false se o Budget acabou
+func (h *hedger) withdraw() bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.tokens < 1 {
+		return false
+	}
+	h.tokens--
+	return true
+}
+
+// discard fecha as respostas das tentativas que perderam
+func discard(results <-chan hedgeResult, pending int) {
+	for range pending {
+		if r := <-results; r.resp != nil {
+			r.resp.Body.Close()
+		}
+	}
+}
+
+// cancelBody libera o ctx da tentativa vencedora quando o corpo é fechado
+// (cancelar antes interromperia a leitura do corpo)
+type cancelBody struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (b *cancelBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.cancel()
+	return err
+}
@@ -0,0 +1,114 @@
+package httpClient
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestHedge(t *testing.T) {
+	tests := []struct {
+		name   string
+		method string
+		slow   bool // A 1ª tentativa só responde quando cancelada
+		calls  int32
+		body   string
+		expect HedgeSnapshot
+	}{
+		{"resposta rápida sem cópia", http.MethodGet, false, 1, "0", HedgeSnapshot{Requests: 1}},
+		{"cópia vence a lenta", http.MethodGet, true, 2, "1", HedgeSnapshot{Requests: 1, Hedges: 1, Wins: 1, WinRate: 1}},
+		{"POST sem cópia", http.MethodPost, false, 1, "0", HedgeSnapshot{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls atomic.Int32
+			canceled := make(chan struct{})
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				n := calls.Add(1) - 1
+				if n == 0 && tt.slow {
+					<-r.Context().Done()
+					close(canceled)
+					return
+				}
+				fmt.Fprint(w, n)
+			}))
+			defer server.Close()
+
+			stats := &HedgeStats{}
+			client := &http.Client{Transport: Chain(nil, Hedge(HedgePolicy{Delay: 20 * time.Millisecond, Stats: stats}))}
+			req, _ := http.NewRequest(tt.method, server.URL, nil)
+			resp, err := client.Do(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			body, _ := io.ReadAll(resp.Body)
+			resp.Body.Close()
+
+			if string(body) != tt.body || calls.Load() != tt.calls {
+				t.Errorf("corpo = %q após %d chamadas; expect %q após %d", body, calls.Load(), tt.body, tt.calls)
+			}
+			if got := stats.Snapshot(); got != tt.expect {
+				t.Errorf("Snapshot() = %+v; expect %+v", got, tt.expect)
+			}
+			if tt.slow {
+				select {
+				case <-canceled:
+				case <-time.After(time.Second):
+					t.Error("a tentativa lenta não foi cancelada")
+				}
+			}
+		})
+	}
+}
+
+func TestHedgeBudget(t *testing.T) {
+	var calls atomic.Int32
+	slow := RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		calls.Add(1)
+		select {
+		case <-time.After(20 * time.Millisecond):
+		case <-req.Context().Done():
+			return nil, req.Context().Err()
+		}
+		return &http.Response{StatusCode: http.StatusOK, Body: http.NoBody}, nil
+	})
+
+	stats := &HedgeStats{}
+	rt := Chain(slow, Hedge(HedgePolicy{Delay: time.Millisecond, Budget: 0.01, Stats: stats}))
+	for range 12 {
+		resp, err := rt.RoundTrip(httptest.NewRequest(http.MethodGet, "http://exemplo", nil))
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+	}
+
+	// Começa com hedgeBurst fichas e ganha só 0.01 por requisição
+	s := stats.Snapshot()
+	if s.Requests != 12 || s.Hedges != hedgeBurst || s.Denied != 2 || calls.Load() != 12+hedgeBurst {
+		t.Errorf("Snapshot() = %+v após %d chamadas; expect %d cópias e 2 negadas", s, calls.Load(), hedgeBurst)
+	}
+}
+
+func TestHedgeAllFail(t *testing.T) {
+	var calls atomic.Int32
+	failing := RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		calls.Add(1)
+		time.Sleep(10 * time.Millisecond)
+		return nil, io.ErrUnexpectedEOF
+	})
+
+	rt := Chain(failing, Hedge(HedgePolicy{Delay: time.Millisecond}))
+	req := httptest.NewRequest(http.MethodPut, "http://exemplo", strings.NewReader("corpo"))
+	req.GetBody = func() (io.ReadCloser, error) { return io.NopCloser(strings.NewReader("corpo")), nil }
+
+	if _, err := rt.RoundTrip(req); err != io.ErrUnexpectedEOF || calls.Load() != 2 {
+		t.Errorf("RoundTrip() erro = %v após %d chamadas; expect ErrUnexpectedEOF após 2", err, calls.Load())
+	}
+}
@@ -0,0 +1,164 @@
+package httpClient
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"log"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Um RoundTripper não pode alterar a requisição recebida: os middlewares que
+// mudam headers trabalham em um Clone.
+
+// DefaultHeaders envia os headers que a requisição não definiu (ex.: User-Agent)
+func DefaultHeaders(headers http.Header) Middleware {
+	return func(next http.RoundTripper) http.RoundTripper {
+		if len(headers) == 0 {
+			return next
+		}
+		return RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			var clone *http.Request
+			for name, values := range headers {
+				if req.Header.Get(name) != "" {
+					continue
+				}
+				if clone == nil {
+					clone = req.Clone(req.Context())
+				}
+				clone.Header[name] = values
+			}
+			if clone == nil {
+				return next.RoundTrip(req)
+			}
+			return next.RoundTrip(clone)
+		})
+	}
+}
+
+// RequestIDHeader é o header usado por RequestID
+const RequestIDHeader = "X-Request-ID"
+
+type requestIDKey struct{}
+
+// WithRequestID guarda o ID no ctx: as requisições feitas com ele levam esse X-Request-ID
+// (útil para repassar o ID da requisição que o servidor está atendendo)
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+// RequestIDFrom devolve o ID guardado por WithRequestID
+func RequestIDFrom(ctx context.Context) (string, bool) {
+	id, ok := ctx.Value(requestIDKey{}).(string)
+	return id, ok && id != ""
+}
+
+// RequestID envia o X-Request-ID: o da requisição, o do ctx ou um novo aleatório
+func RequestID() Middleware {
+	return func(next http.RoundTripper) http.RoundTripper {
+		return RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			if req.Header.Get(RequestIDHeader) != "" {
+				return next.RoundTrip(req)
+			}
+			id, ok := RequestIDFrom(req.Context())
+			if !ok {
+				id = newRequestID()
+			}
+			req = req.Clone(req.Context())
+			req.Header.Set(RequestIDHeader, id)
+			return next.RoundTrip(req)
+		})
+	}
+}
+
+func newRequestID() string {
+	b := make([]byte, 8)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// Logging registra cada tentativa: método, URL, status (ou erro), duração e request ID.
+// logger nil usa o log padrão.
+func Logging(logger *log.Logger) Middleware {
+	if logger == nil {
+		logger = log.Default()
+	}
+	return func(next http.RoundTripper) http.RoundTripper {
+		return RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			start := time.Now()
+			resp, err := next.RoundTrip(req)
+			elapsed := time.Since(start).Round(time.Millisecond)
+
+			id := req.Header.Get(RequestIDHeader)
+			if err != nil {
+				logger.Printf("%s %s ==> erro: %v (%v) [%s]", req.Method, req.URL.Redacted(), err, elapsed, id)
+			} else {
+				logger.Printf("%s %s ==> %d (%v) [%s]", req.Method, req.URL.Redacted(), resp.StatusCode, elapsed, id)
+			}
+			return resp, err
+		})
+	}
+}
+
+// Metrics conta as requisições feitas por um ou mais clients (use com Measure)
+type Metrics struct {
+	mu       sync.Mutex
+	requests int64
+	errors   int64
+	status   map[string]int64 // "2xx", "4xx", ...
+	total    time.Duration
+}
+
+// MetricsSnapshot é uma cópia dos contadores, pronta para virar JSON
+type MetricsSnapshot struct {
+	Requests  int64            `json:"requests"`
+	Errors    int64            `json:"errors"` // Falhas de rede (sem resposta)
+	Status    map[string]int64 `json:"status"`
+	AvgMillis float64          `json:"avg_ms"`
+}
+
+// Measure registra em m cada tentativa que passa pelo middleware
+func Measure(m *Metrics) Middleware {
+	return func(next http.RoundTripper) http.RoundTripper {
+		return RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			start := time.Now()
+			resp, err := next.RoundTrip(req)
+			m.record(resp, err, time.Since(start))
+			return resp, err
+		})
+	}
+}
+
+func (m *Metrics) record(resp *http.Response, err error, elapsed time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.requests++
+	m.total += elapsed
+	if err != nil {
+		m.errors++
+		return
+	}
+	if m.status == nil {
+		m.status = make(map[string]int64)
+	}
+	m.status[strconv.Itoa(resp.StatusCode/100)+"xx"]++
+}
+
+// Snapshot devolve os contadores atuais
+func (m *Metrics) Snapshot() MetricsSnapshot {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	s := MetricsSnapshot{Requests: m.requests, Errors: m.errors, Status: make(map[string]int64, len(m.status))}
+	for class, n := range m.status {
+		s.Status[class] = n
+	}
+	if m.requests > 0 {
+		s.AvgMillis = float64(m.total.Microseconds()) / float64(m.requests) / 1000
+	}
+	return s
+}
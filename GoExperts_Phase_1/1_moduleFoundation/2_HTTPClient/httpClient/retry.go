@@ -0,0 +1,127 @@
+package httpClient
+
+import (
+	"context"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// RetryPolicy define quantas tentativas fazer e quanto esperar entre elas
+type RetryPolicy struct {
+	MaxAttempts int           // Total de tentativas (1 = sem retry)
+	BaseDelay   time.Duration // Espera antes da 2ª tentativa; dobra a cada nova tentativa
+	MaxDelay    time.Duration // Teto da espera entre tentativas (e do Retry-After aceito)
+}
+
+// Backoff calcula a espera antes da tentativa seguinte à tentativa attempt
+// (exponencial + jitter de até 50%, para vários clients não repetirem juntos)
+func (p RetryPolicy) Backoff(attempt int) time.Duration {
+	delay := p.BaseDelay << (attempt - 1)
+	if p.MaxDelay > 0 && (delay > p.MaxDelay || delay <= 0) {
+		delay = p.MaxDelay
+	}
+	if delay <= 0 {
+		return 0
+	}
+	return delay/2 + rand.N(delay/2+1)
+}
+
+// Sleep espera d ou até o context ser cancelado
+func Sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Retry repete as requisições idempotentes (GET, HEAD, OPTIONS, PUT, DELETE ou
+// com header Idempotency-Key) que falham na rede ou respondem 429/5xx.
+// Um Retry-After maior que MaxDelay não é esperado: a resposta volta como veio.
+// Corpo só é reenviado se a requisição tiver GetBody (o http.NewRequest preenche
+// para bytes.Reader, strings.Reader e bytes.Buffer).
+func Retry(policy RetryPolicy) Middleware {
+	return func(next http.RoundTripper) http.RoundTripper {
+		return RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			if policy.MaxAttempts <= 1 || !retryable(req) {
+				return next.RoundTrip(req)
+			}
+
+			for attempt := 1; ; attempt++ {
+				try := req
+				if attempt > 1 && req.Body != nil && req.Body != http.NoBody {
+					body, err := req.GetBody()
+					if err != nil {
+						return nil, err
+					}
+					try = req.Clone(req.Context())
+					try.Body = body
+				}
+
+				resp, err := next.RoundTrip(try)
+				if attempt == policy.MaxAttempts || req.Context().Err() != nil {
+					return resp, err
+				}
+
+				wait := policy.Backoff(attempt)
+				if err == nil {
+					if !transientStatus(resp.StatusCode) {
+						return resp, nil
+					}
+					if after, ok := retryAfter(resp); ok {
+						if policy.MaxDelay > 0 && after > policy.MaxDelay {
+							return resp, nil // O servidor pediu para esperar mais do que aceitamos
+						}
+						wait = after
+					}
+					// Descarta a resposta para a conexão voltar ao pool
+					io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+					resp.Body.Close()
+				}
+
+				if err := Sleep(req.Context(), wait); err != nil {
+					return nil, err
+				}
+			}
+		})
+	}
+}
+
+// retryable diz se a requisição pode ser repetida sem efeito colateral duplicado
+func retryable(req *http.Request) bool {
+	if req.Body != nil && req.Body != http.NoBody && req.GetBody == nil {
+		return false
+	}
+	switch req.Method {
+	case "", http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return req.Header.Get("Idempotency-Key") != ""
+}
+
+// transientStatus são as respostas que valem uma nova tentativa
+func transientStatus(code int) bool {
+	return code == http.StatusTooManyRequests || (code >= 500 && code != http.StatusNotImplemented)
+}
+
+// retryAfter lê o header Retry-After em segundos ou como data
+func retryAfter(resp *http.Response) (time.Duration, bool) {
+	value := resp.Header.Get("Retry-After")
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if t, err := http.ParseTime(value); err == nil {
+		return max(time.Until(t), 0), true
+	}
+	return 0, false
+}
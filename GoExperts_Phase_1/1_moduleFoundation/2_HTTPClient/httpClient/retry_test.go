@@ -0,0 +1,142 @@
+package httpClient
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// sequence responde com os status na ordem; depois do último repete o último
+type sequence struct {
+	status     []int
+	retryAfter string
+	calls      int
+	bodies     []string
+}
+
+func (s *sequence) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+	s.bodies = append(s.bodies, string(body))
+	code := s.status[min(s.calls, len(s.status)-1)]
+	s.calls++
+	if s.retryAfter != "" {
+		w.Header().Set("Retry-After", s.retryAfter)
+	}
+	w.WriteHeader(code)
+}
+
+var fastRetry = RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond, MaxDelay: 5 * time.Millisecond}
+
+func TestRetry(t *testing.T) {
+	tests := []struct {
+		name       string
+		method     string
+		header     string // Idempotency-Key
+		status     []int
+		retryAfter string
+		calls      int
+		expect     int
+	}{
+		{"sucesso direto", http.MethodGet, "", []int{200}, "", 1, 200},
+		{"503 e depois 200", http.MethodGet, "", []int{503, 503, 200}, "", 3, 200},
+		{"esgota as tentativas", http.MethodGet, "", []int{503}, "", 3, 503},
+		{"429", http.MethodGet, "", []int{429, 200}, "", 2, 200},
+		{"404 não repete", http.MethodGet, "", []int{404}, "", 1, 404},
+		{"501 não repete", http.MethodGet, "", []int{501}, "", 1, 501},
+		{"POST não repete", http.MethodPost, "", []int{503, 200}, "", 1, 503},
+		{"POST com Idempotency-Key", http.MethodPost, "abc", []int{503, 200}, "", 2, 200},
+		{"PUT repete com corpo", http.MethodPut, "", []int{503, 200}, "", 2, 200},
+		{"Retry-After curto", http.MethodGet, "", []int{503, 200}, "0", 2, 200},
+		{"Retry-After longo demais", http.MethodGet, "", []int{503, 200}, "120", 1, 503},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			seq := &sequence{status: tt.status, retryAfter: tt.retryAfter}
+			server := httptest.NewServer(seq)
+			defer server.Close()
+
+			client := &http.Client{Transport: Chain(nil, Retry(fastRetry))}
+			req, _ := http.NewRequest(tt.method, server.URL, strings.NewReader("corpo"))
+			if tt.header != "" {
+				req.Header.Set("Idempotency-Key", tt.header)
+			}
+			resp, err := client.Do(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			resp.Body.Close()
+
+			if resp.StatusCode != tt.expect || seq.calls != tt.calls {
+				t.Errorf("status = %d após %d chamadas; expect %d após %d", resp.StatusCode, seq.calls, tt.expect, tt.calls)
+			}
+			for i, body := range seq.bodies {
+				if body != "corpo" {
+					t.Errorf("tentativa %d recebeu corpo %q; expect corpo", i+1, body)
+				}
+			}
+		})
+	}
+}
+
+func TestRetryNetworkError(t *testing.T) {
+	calls := 0
+	failing := RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		calls++
+		if calls < 2 {
+			return nil, io.ErrUnexpectedEOF
+		}
+		return &http.Response{StatusCode: http.StatusOK, Body: http.NoBody}, nil
+	})
+
+	resp, err := Chain(failing, Retry(fastRetry)).RoundTrip(httptest.NewRequest(http.MethodGet, "http://exemplo", nil))
+	if err != nil || resp.StatusCode != http.StatusOK || calls != 2 {
+		t.Errorf("RoundTrip() = %v, %v após %d chamadas; expect 200 após 2", resp, err, calls)
+	}
+}
+
+func TestRetryContextCanceled(t *testing.T) {
+	calls := 0
+	failing := RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		calls++
+		return nil, io.ErrUnexpectedEOF
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	req := httptest.NewRequest(http.MethodGet, "http://exemplo", nil).WithContext(ctx)
+	slow := RetryPolicy{MaxAttempts: 5, BaseDelay: time.Second, MaxDelay: time.Second}
+
+	start := time.Now()
+	_, err := Chain(failing, Retry(slow)).RoundTrip(req)
+	if err != context.DeadlineExceeded || calls != 1 {
+		t.Errorf("RoundTrip() erro = %v após %d chamadas; expect DeadlineExceeded após 1", err, calls)
+	}
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("RoundTrip() levou %v; o ctx não interrompeu a espera", elapsed)
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	p := RetryPolicy{BaseDelay: 100 * time.Millisecond, MaxDelay: 300 * time.Millisecond}
+	tests := []struct {
+		attempt  int
+		min, max time.Duration
+	}{
+		{1, 50 * time.Millisecond, 100 * time.Millisecond},
+		{2, 100 * time.Millisecond, 200 * time.Millisecond},
+		{3, 150 * time.Millisecond, 300 * time.Millisecond},  // Teto de 300ms
+		{70, 150 * time.Millisecond, 300 * time.Millisecond}, // Sem overflow
+	}
+	for _, tt := range tests {
+		for range 20 {
+			if got := p.Backoff(tt.attempt); got < tt.min || got > tt.max {
+				t.Fatalf("Backoff(%d) = %v; expect entre %v e %v", tt.attempt, got, tt.min, tt.max)
+			}
+		}
+	}
+}
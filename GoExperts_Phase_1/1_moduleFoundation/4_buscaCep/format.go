@@ -0,0 +1,103 @@
+package main
+
+import (
+	"GoProject/1_moduleFoundation/5_cep-handler/getCep"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"text/tabwriter"
+)
+
+// writerFunc escreve os endereços em um formato de saída
+type writerFunc func(w io.Writer, addrs []*getCep.ViaCEP) error
+
+// writers mapeia o valor de -format para a função que escreve a saída
+var writers = map[string]writerFunc{
+	"json":  writeJSON,
+	"csv":   writeCSV,
+	"table": writeTable,
+	"text":  writeText,
+}
+
+// extensions define a extensão dos arquivos gravados com -dir
+var extensions = map[string]string{
+	"json":  ".json",
+	"csv":   ".csv",
+	"table": ".txt",
+	"text":  ".txt",
+}
+
+// csvHeader são as colunas do formato csv (mesmos nomes das tags json da ViaCEP)
+var csvHeader = []string{"cep", "logradouro", "complemento", "unidade", "bairro", "localidade", "uf", "estado", "regiao", "ibge", "gia", "ddd", "siafi"}
+
+// writeJSON escreve sempre um array, mesmo com um só endereço encontrado:
+// quem lê a saída não precisa tratar dois formatos
+func writeJSON(w io.Writer, addrs []*getCep.ViaCEP) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	if addrs == nil {
+		addrs = []*getCep.ViaCEP{} // "[]" em vez de "null"
+	}
+	return encoder.Encode(addrs)
+}
+
+// writeJSONObject escreve cada endereço como um objeto; usado nos arquivos do -dir
+func writeJSONObject(w io.Writer, addrs []*getCep.ViaCEP) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	for _, a := range addrs {
+		if err := encoder.Encode(a); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func writeCSV(w io.Writer, addrs []*getCep.ViaCEP) error {
+	writer := csv.NewWriter(w)
+	writer.Write(csvHeader)
+	for _, a := range addrs {
+		writer.Write([]string{a.Cep, a.Logradouro, a.Complemento, a.Unidade, a.Bairro, a.Localidade, a.Uf, a.Estado, a.Regiao, a.Ibge, a.Gia, a.Ddd, a.Siafi})
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+func writeTable(w io.Writer, addrs []*getCep.ViaCEP) error {
+	table := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(table, "CEP\tLOGRADOURO\tBAIRRO\tLOCALIDADE\tUF\tDDD")
+	for _, a := range addrs {
+		fmt.Fprintf(table, "%s\t%s\t%s\t%s\t%s\t%s\n", a.Cep, a.Logradouro, a.Bairro, a.Localidade, a.Uf, a.Ddd)
+	}
+	return table.Flush()
+}
+
+// writeText escreve um bloco "Campo: valor" por endereço (campos vazios são omitidos)
+func writeText(w io.Writer, addrs []*getCep.ViaCEP) error {
+	for i, a := range addrs {
+		if i > 0 {
+			if _, err := fmt.Fprintln(w); err != nil {
+				return err
+			}
+		}
+		fields := []struct{ label, value string }{
+			{"CEP", a.Cep},
+			{"Logradouro", a.Logradouro},
+			{"Complemento", a.Complemento},
+			{"Bairro", a.Bairro},
+			{"Localidade", a.Localidade},
+			{"UF", a.Uf},
+			{"DDD", a.Ddd},
+		}
+		for _, f := range fields {
+			if f.value == "" {
+				continue
+			}
+			if _, err := fmt.Fprintf(w, "%s: %s\n", f.label, f.value); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
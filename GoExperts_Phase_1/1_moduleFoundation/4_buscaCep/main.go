@@ -1,80 +1,187 @@
 package main
 
 import (
-	"encoding/json"
+	"GoProject/1_moduleFoundation/5_cep-handler/getCep"
+	"bufio"
+	"context"
+	"flag"
 	"fmt"
 	"io"
-	"net/http"
 	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"time"
 )
 
-// Cria a struct
-type ViaCEP struct {
-	Cep         string `json:"cep"`
-	Logradouro  string `json:"logradouro"`
-	Complemento string `json:"complemento"`
-	Unidade     string `json:"unidade"`
-	Bairro      string `json:"bairro"`
-	Localidade  string `json:"localidade"`
-	Uf          string `json:"uf"`
-	Estado      string `json:"estado"`
-	Regiao      string `json:"regiao"`
-	Ibge        string `json:"ibge"`
-	Gia         string `json:"gia"`
-	Ddd         string `json:"ddd"`
-	Siafi       string `json:"siafi"`
-}
-
 // Importante!
-// Para testar digite no terminal passando o argumento "cep":
-// go run main.go 14093070
+// Para testar digite no terminal passando os CEPs como argumento:
+// go run . 14093070 01001-000
+//
+// Outros exemplos:
+// go run . -format=table 14093070 01001000
+// go run . -format=csv -out=enderecos.csv 14093070 01001000
+// go run . -format=json -dir=enderecos 14093070 01001000   ==> um arquivo por CEP
+// cat ceps.txt | go run . -format=table                    ==> CEPs pela entrada padrão
+// go run . -input=ceps.txt -concurrency=10
+// go run . -viacep-url=http://localhost:8081/ws/ 01001000  ==> usa o cmd/fakeviacep (sem internet)
 
+// Códigos de saída
+const (
+	exitOK     = 0 // Todos os CEPs foram encontrados
+	exitFailed = 1 // Pelo menos um CEP falhou
+	exitUsage  = 2 // Flags ou entrada inválidas
+)
 
 func main() {
-	for _, cep := range os.Args[1:] {
-		req, err := http.Get("http://viacep.com.br/ws/" + cep + "/json/") // Executa a requisição com o argumento passado
-		if err != nil {
-			fmt.Fprintf(os.Stderr, "Erro ao fazer requisição: %v\n", err)
-		}
-		defer req.Body.Close()
-		res, err := io.ReadAll(req.Body)
-		if err != nil {
-			fmt.Fprintf(os.Stderr, "Erro ao ler resposta: %v\n", err)
-		}
-		// fmt.Println(string(res)) // Imprime no formato de string
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
 
-		var data ViaCEP
-		err = json.Unmarshal(res, &data) // Parse para o formato da struct
-		if err != nil {
-			fmt.Fprintf(os.Stderr, "Erro ao fazer parse da resposta: %v\n", err)
+// run executa o CLI e devolve o código de saída (separado de main para facilitar os testes)
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("buscaCep", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	format := flags.String("format", "text", "formato de saída: json, csv, table ou text")
+	out := flags.String("out", "", "arquivo único com todos os endereços (padrão: saída padrão)")
+	dir := flags.String("dir", "", "diretório para gravar um arquivo por CEP")
+	input := flags.String("input", "", "arquivo com CEPs, um por linha (\"-\" = entrada padrão)")
+	concurrency := flags.Int("concurrency", 5, "buscas simultâneas")
+	timeout := flags.Duration("timeout", 30*time.Second, "tempo máximo para todas as buscas")
+	viaCEPURL := flags.String("viacep-url", "", "usa só a ViaCEP neste endereço (padrão: $"+getCep.ViaCEPURLEnv+" ou a API pública)")
+	if err := flags.Parse(args); err != nil {
+		return exitUsage
+	}
+	if *viaCEPURL != "" {
+		getCep.UseViaCEP(*viaCEPURL)
+	}
+
+	writer, ok := writers[*format]
+	if !ok {
+		fmt.Fprintf(stderr, "Formato inválido: %q (use json, csv, table ou text)\n", *format)
+		return exitUsage
+	}
+	if *out != "" && *dir != "" {
+		fmt.Fprintln(stderr, "Use -out ou -dir, não os dois")
+		return exitUsage
+	}
+
+	ceps, err := collectCEPs(flags.Args(), *input, stdin)
+	if err != nil {
+		fmt.Fprintf(stderr, "Erro ao ler CEPs: %v\n", err)
+		return exitUsage
+	}
+	if len(ceps) == 0 {
+		fmt.Fprintln(stderr, "Nenhum CEP informado. Ex.: go run . 14093070")
+		return exitUsage
+	}
+
+	// Ctrl+C cancela as buscas em andamento
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	ctx, cancel := context.WithTimeout(ctx, *timeout)
+	defer cancel()
+
+	results := getCep.LookupBatch(ctx, ceps, *concurrency)
+
+	// Erros vão para a saída de erro; só os endereços encontrados vão para a saída
+	code := exitOK
+	var found []*getCep.ViaCEP
+	for _, res := range results {
+		if res.Err != nil {
+			fmt.Fprintf(stderr, "Erro ao buscar %s: %v\n", res.Cep, res.Err)
+			code = exitFailed
+			continue
 		}
-		// fmt.Println(data) // Imprime no formato da struct
+		found = append(found, res.Address)
+	}
 
-		// Criação de arquivo
-		file, err := os.Create("Endereco.txt")
+	switch {
+	case *dir != "":
+		err = writeDir(*dir, *format, writer, found)
+	case *out != "":
+		err = writeFile(*out, writer, found)
+	default:
+		err = writer(stdout, found)
+	}
+	if err != nil {
+		fmt.Fprintf(stderr, "Erro ao escrever endereços: %v\n", err)
+		return exitFailed
+	}
+	return code
+}
+
+// collectCEPs junta os CEPs dos argumentos e do arquivo/entrada padrão.
+// Sem argumentos e sem -input, lê da entrada padrão.
+func collectCEPs(args []string, input string, stdin io.Reader) ([]string, error) {
+	ceps := append([]string{}, args...)
+
+	if input == "" && len(args) == 0 {
+		input = "-"
+	}
+	switch input {
+	case "":
+		return ceps, nil
+	case "-":
+		more, err := readCEPs(stdin)
+		return append(ceps, more...), err
+	default:
+		file, err := os.Open(input)
 		if err != nil {
-			fmt.Fprintf(os.Stderr, "Erro ao criar um arquivo: %v\n", err)
+			return nil, err
 		}
 		defer file.Close()
+		more, err := readCEPs(file)
+		return append(ceps, more...), err
+	}
+}
 
-		// Escrita
-		_, err = file.WriteString(fmt.Sprintf("Localidade: %s", data.Localidade)) // fmt.Sprintf - Não necessita salvar em variável
-		if err != nil {
-			fmt.Fprintf(os.Stderr, "Erro ao escrever em um arquivo: %v\n", err)
+// readCEPs lê um CEP por linha; aceita também vírgulas e ignora linhas vazias e comentários (#)
+func readCEPs(r io.Reader) ([]string, error) {
+	var ceps []string
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
 		}
-
-		// Leitura
-		arquivo, err := os.ReadFile("Endereco.txt")
-		if err != nil {
-			panic(err)
+		for _, field := range strings.Split(line, ",") {
+			if field = strings.TrimSpace(field); field != "" {
+				ceps = append(ceps, field)
+			}
 		}
-		fmt.Println(string(arquivo))
 	}
+	return ceps, scanner.Err()
 }
 
+// writeFile grava todos os endereços em um único arquivo
+func writeFile(path string, writer writerFunc, addrs []*getCep.ViaCEP) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := writer(file, addrs); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close() // Sem defer: cada arquivo é fechado (e o erro checado) na hora
+}
 
+// writeDir grava um arquivo por CEP: <dir>/<cep>.<extensão do formato>
+func writeDir(dir, format string, writer writerFunc, addrs []*getCep.ViaCEP) error {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	if format == "json" {
+		writer = writeJSONObject // Um objeto por arquivo, não um array de 1
+	}
+	for _, addr := range addrs {
+		name := strings.ReplaceAll(addr.Cep, "-", "") + extensions[format]
+		if err := writeFile(filepath.Join(dir, name), writer, []*getCep.ViaCEP{addr}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
 
-// Para abrir o arquivo no terminal digite: cat endereco.txt
-
-// go build -o cep main.go ===> Cria um arquivo cep
-// ./cep 14093070 ==> Executa o arquivo passando o parâmetro
\ No newline at end of file
+// go build -o cep . ===> Cria um arquivo cep
+// ./cep 14093070 ==> Executa o arquivo passando o parâmetro
@@ -0,0 +1,154 @@
+package main
+
+import (
+	"GoProject/1_moduleFoundation/5_cep-handler/getCep"
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// mapProvider responde a partir de um map, sem acessar a rede
+type mapProvider map[string]*getCep.ViaCEP
+
+func (m mapProvider) Name() string { return "map" }
+
+func (m mapProvider) Lookup(ctx context.Context, cep string) (*getCep.ViaCEP, error) {
+	if addr, ok := m[cep]; ok {
+		return addr, nil
+	}
+	return nil, getCep.ErrCEPNotFound
+}
+
+func useFakeProvider(t *testing.T) {
+	t.Helper()
+	previous := getCep.DefaultProvider
+	getCep.DefaultProvider = mapProvider{
+		"14093070": {Cep: "14093-070", Logradouro: "Rua Exemplo", Bairro: "Centro", Localidade: "Ribeirão Preto", Uf: "SP", Ddd: "16"},
+		"01001000": {Cep: "01001-000", Logradouro: "Praça da Sé", Bairro: "Sé", Localidade: "São Paulo", Uf: "SP", Ddd: "11"},
+	}
+	t.Cleanup(func() { getCep.DefaultProvider = previous })
+}
+
+func TestRunFormats(t *testing.T) {
+	useFakeProvider(t)
+
+	tests := []struct {
+		format string
+		expect []string
+	}{
+		{"text", []string{"CEP: 14093-070\nLogradouro: Rua Exemplo", "\n\nCEP: 01001-000"}},
+		{"json", []string{`"localidade": "Ribeirão Preto"`, `"cep": "01001-000"`}},
+		{"csv", []string{"cep,logradouro,complemento", "14093-070,Rua Exemplo,,,Centro,Ribeirão Preto,SP"}},
+		{"table", []string{"CEP        LOGRADOURO", "01001-000  Praça da Sé"}},
+	}
+
+	for _, item := range tests {
+		t.Run(item.format, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			code := run([]string{"-format=" + item.format, "14093070", "01001-000"}, nil, &stdout, &stderr)
+
+			if code != exitOK {
+				t.Fatalf("código de saída = %d; expect %d (stderr: %s)", code, exitOK, stderr.String())
+			}
+			for _, e := range item.expect {
+				if !strings.Contains(stdout.String(), e) {
+					t.Errorf("saída não contém %q:\n%s", e, stdout.String())
+				}
+			}
+		})
+	}
+}
+
+func TestRunExitCodes(t *testing.T) {
+	useFakeProvider(t)
+
+	tests := []struct {
+		name   string
+		args   []string
+		expect int
+	}{
+		{"todos encontrados", []string{"14093070"}, exitOK},
+		{"um não encontrado", []string{"14093070", "01009999"}, exitFailed},
+		{"um inválido", []string{"abc", "14093070"}, exitFailed},
+		{"formato desconhecido", []string{"-format=xml", "14093070"}, exitUsage},
+		{"out e dir juntos", []string{"-out=a.txt", "-dir=b", "14093070"}, exitUsage},
+	}
+
+	for _, item := range tests {
+		t.Run(item.name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			if code := run(item.args, nil, &stdout, &stderr); code != item.expect {
+				t.Errorf("run(%v) = %d; expect %d (stderr: %s)", item.args, code, item.expect, stderr.String())
+			}
+		})
+	}
+}
+
+func TestRunStdin(t *testing.T) {
+	useFakeProvider(t)
+
+	stdin := strings.NewReader("# CEPs do teste\n14093-070\n\n01001000, 14093070\n")
+	var stdout, stderr bytes.Buffer
+	code := run([]string{"-format=table"}, stdin, &stdout, &stderr)
+
+	if code != exitOK {
+		t.Fatalf("código de saída = %d; expect %d (stderr: %s)", code, exitOK, stderr.String())
+	}
+	if lines := strings.Count(stdout.String(), "\n"); lines != 4 {
+		t.Errorf("linhas = %d; expect 4 (cabeçalho + 3 CEPs):\n%s", lines, stdout.String())
+	}
+}
+
+func TestRunDir(t *testing.T) {
+	useFakeProvider(t)
+	dir := filepath.Join(t.TempDir(), "enderecos")
+
+	var stdout, stderr bytes.Buffer
+	if code := run([]string{"-format=json", "-dir=" + dir, "14093070", "01001000"}, nil, &stdout, &stderr); code != exitOK {
+		t.Fatalf("código de saída = %d (stderr: %s)", code, stderr.String())
+	}
+
+	for cep, city := range map[string]string{"14093070": "Ribeirão Preto", "01001000": "São Paulo"} {
+		data, err := os.ReadFile(filepath.Join(dir, cep+".json"))
+		if err != nil {
+			t.Fatalf("arquivo do CEP %s: %v", cep, err)
+		}
+		if !strings.Contains(string(data), city) || !strings.HasPrefix(string(data), "{") {
+			t.Errorf("%s.json não é um objeto com %q:\n%s", cep, city, data)
+		}
+	}
+}
+
+// TestRunJSONArray confere que a saída json é sempre um array, mesmo quando só um CEP é encontrado
+func TestRunJSONArray(t *testing.T) {
+	useFakeProvider(t)
+
+	tests := []struct {
+		name   string
+		ceps   []string
+		expect int
+	}{
+		{"nenhum encontrado", []string{"99999999"}, 0},
+		{"um encontrado", []string{"14093070", "99999999"}, 1},
+		{"dois encontrados", []string{"14093070", "01001000"}, 2},
+	}
+
+	for _, item := range tests {
+		t.Run(item.name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			run(append([]string{"-format=json"}, item.ceps...), nil, &stdout, &stderr)
+
+			var addrs []getCep.ViaCEP
+			if err := json.Unmarshal(stdout.Bytes(), &addrs); err != nil {
+				t.Fatalf("saída não é um array JSON: %v\n%s", err, stdout.String())
+			}
+			if len(addrs) != item.expect {
+				t.Errorf("%d endereços; expect %d", len(addrs), item.expect)
+			}
+		})
+	}
+}
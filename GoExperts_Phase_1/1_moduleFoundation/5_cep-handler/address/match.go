@@ -0,0 +1,162 @@
+package address
+
+import (
+	"GoProject/1_moduleFoundation/5_cep-handler/getCep"
+	"math"
+	"strings"
+)
+
+// MatchThreshold é a nota mínima para considerar que o endereço digitado é o do CEP
+const MatchThreshold = 0.8
+
+// Pesos de cada campo na nota final; campos não informados saem da conta
+var weights = map[string]float64{
+	"logradouro": 0.6,
+	"bairro":     0.1,
+	"localidade": 0.25,
+	"uf":         0.05,
+}
+
+// Input é o endereço como o usuário digitou
+type Input struct {
+	Logradouro string `json:"logradouro"`
+	Bairro     string `json:"bairro"`
+	Localidade string `json:"localidade"`
+	Uf         string `json:"uf"`
+}
+
+// Result é a comparação do Input com o endereço do CEP
+type Result struct {
+	Match  bool               `json:"match"`
+	Score  float64            `json:"score"`  // 0 a 1, média ponderada dos campos
+	Fields map[string]float64 `json:"fields"` // Nota de cada campo comparado
+}
+
+// Compare dá uma nota para o quanto o Input corresponde ao endereço da ViaCEP.
+// Uma UF diferente nunca é match, qualquer que seja a nota.
+func Compare(in Input, addr *getCep.ViaCEP) Result {
+	res := Result{Fields: map[string]float64{}}
+	if addr == nil {
+		return res
+	}
+
+	pairs := []struct{ field, typed, expect string }{
+		{"logradouro", in.Logradouro, addr.Logradouro},
+		{"bairro", in.Bairro, addr.Bairro},
+		{"localidade", in.Localidade, addr.Localidade},
+		{"uf", in.Uf, addr.Uf},
+	}
+
+	var total, weight float64
+	for _, p := range pairs {
+		// CEP geral de cidade pequena não tem logradouro nem bairro para comparar
+		if strings.TrimSpace(p.typed) == "" || p.expect == "" {
+			continue
+		}
+		score := Similarity(p.typed, p.expect)
+		if p.field == "uf" && !strings.EqualFold(strings.TrimSpace(p.typed), p.expect) {
+			score = 0
+		}
+		res.Fields[p.field] = round(score)
+		total += score * weights[p.field]
+		weight += weights[p.field]
+	}
+	if weight == 0 {
+		return res
+	}
+
+	res.Score = round(total / weight)
+	res.Match = res.Score >= MatchThreshold && (in.Uf == "" || res.Fields["uf"] == 1)
+	return res
+}
+
+// Similarity normaliza os dois textos (abreviações, acentos, caixa) e devolve
+// de 0 (nada a ver) a 1 (iguais). O tipo de logradouro só pesa
+// quando os dois lados informam: "Paulista" x "Avenida Paulista" = 1.
+func Similarity(a, b string) float64 {
+	ta, tb := strings.Fields(Normalize(a)), strings.Fields(Normalize(b))
+	if len(ta) == 0 || len(tb) == 0 {
+		return 0
+	}
+
+	penalty := 1.0
+	if len(ta) > 1 && len(tb) > 1 && streetTypes[ta[0]] && streetTypes[tb[0]] {
+		if ta[0] != tb[0] {
+			penalty = 0.9 // "Rua Paulista" x "Avenida Paulista"
+		}
+		ta, tb = ta[1:], tb[1:]
+	} else {
+		ta, tb = dropStreetType(ta), dropStreetType(tb)
+	}
+
+	// O maior entre a semelhança letra a letra (erros de digitação) e a
+	// semelhança por palavras (palavras faltando ou fora de ordem)
+	chars := ratio(strings.Join(ta, " "), strings.Join(tb, " "))
+	return penalty * math.Max(chars, tokenDice(ta, tb))
+}
+
+func dropStreetType(tokens []string) []string {
+	if len(tokens) > 1 && streetTypes[tokens[0]] {
+		return tokens[1:]
+	}
+	return tokens
+}
+
+// tokenDice é 2*comuns/(total de palavras); uma palavra conta como comum se
+// tiver ao menos 0.8 de semelhança com uma palavra ainda não usada do outro lado
+func tokenDice(a, b []string) float64 {
+	used := make([]bool, len(b))
+	common := 0.0
+	for _, wa := range a {
+		best, at := 0.0, -1
+		for j, wb := range b {
+			if used[j] {
+				continue
+			}
+			if r := ratio(wa, wb); r > best {
+				best, at = r, j
+			}
+		}
+		if best >= 0.8 {
+			used[at] = true
+			common += best
+		}
+	}
+	return 2 * common / float64(len(a)+len(b))
+}
+
+// ratio é 1 - distância de Levenshtein / tamanho do maior texto
+func ratio(a, b string) float64 {
+	ra, rb := []rune(a), []rune(b)
+	longest := max(len(ra), len(rb))
+	if longest == 0 {
+		return 1
+	}
+	return 1 - float64(levenshtein(ra, rb))/float64(longest)
+}
+
+// levenshtein conta inserções, remoções e trocas para ir de a até b
+func levenshtein(a, b []rune) int {
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(b)]
+}
+
+// round deixa a nota com 2 casas para a resposta não ficar com 0.8333333
+func round(f float64) float64 {
+	return math.Round(f*100) / 100
+}
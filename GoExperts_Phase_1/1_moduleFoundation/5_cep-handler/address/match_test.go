@@ -0,0 +1,64 @@
+package address
+
+import (
+	"GoProject/1_moduleFoundation/5_cep-handler/getCep"
+	"testing"
+)
+
+func TestSimilarity(t *testing.T) {
+	tests := []struct {
+		a, b     string
+		min, max float64
+	}{
+		{"Av. Paulista", "Avenida Paulista", 1, 1},
+		{"paulista", "Avenida Paulista", 1, 1}, // Sem o tipo de logradouro
+		{"Rua Paulista", "Avenida Paulista", 0.85, 0.95},
+		{"Av. Paulsita", "Avenida Paulista", 0.7, 0.9}, // Erro de digitação
+		{"Capitão Adelmio Silva", "Rua Capitão Adelmio Norberto da Silva", 0.7, 0.9},
+		{"Rua Augusta", "Avenida Paulista", 0, 0.7},
+		{"", "Avenida Paulista", 0, 0},
+	}
+
+	for _, tt := range tests {
+		got := Similarity(tt.a, tt.b)
+		if got < tt.min || got > tt.max {
+			t.Errorf("Similarity(%q, %q) = %.2f; expect entre %.2f e %.2f", tt.a, tt.b, got, tt.min, tt.max)
+		}
+		if back := Similarity(tt.b, tt.a); back != got {
+			t.Errorf("Similarity não é simétrica: %.2f x %.2f", got, back)
+		}
+	}
+}
+
+func TestCompare(t *testing.T) {
+	addr := &getCep.ViaCEP{Cep: "01310-200", Logradouro: "Avenida Paulista", Bairro: "Bela Vista", Localidade: "São Paulo", Uf: "SP"}
+
+	tests := []struct {
+		name  string
+		in    Input
+		match bool
+	}{
+		{"igual", Input{Logradouro: "Avenida Paulista", Bairro: "Bela Vista", Localidade: "São Paulo", Uf: "SP"}, true},
+		{"abreviado sem acento", Input{Logradouro: "av paulista", Localidade: "sao paulo", Uf: "sp"}, true},
+		{"só logradouro", Input{Logradouro: "Av. Paulista"}, true},
+		{"outra rua", Input{Logradouro: "Rua Augusta", Localidade: "São Paulo", Uf: "SP"}, false},
+		{"outra uf", Input{Logradouro: "Avenida Paulista", Localidade: "São Paulo", Uf: "RJ"}, false},
+		{"vazio", Input{}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res := Compare(tt.in, addr)
+			if res.Match != tt.match {
+				t.Errorf("Compare() = %+v; expect match %v", res, tt.match)
+			}
+		})
+	}
+
+	// CEP geral (sem logradouro): compara só a cidade
+	city := &getCep.ViaCEP{Cep: "14470-000", Localidade: "Guaíra", Uf: "SP"}
+	res := Compare(Input{Logradouro: "Rua 10", Localidade: "guaira"}, city)
+	if !res.Match || len(res.Fields) != 1 {
+		t.Errorf("Compare(cep geral) = %+v; expect match só pela localidade", res)
+	}
+}
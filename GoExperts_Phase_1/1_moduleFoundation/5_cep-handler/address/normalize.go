@@ -0,0 +1,123 @@
+package address
+
+import (
+	"GoProject/1_moduleFoundation/5_cep-handler/municipios"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// abbreviations expande as abreviações comuns em endereços. A chave é a
+// abreviação já sem acento, ponto e em minúsculas ("Pça." ==> "pca").
+var abbreviations = map[string]string{
+	// Tipos de logradouro
+	"r": "rua", "av": "avenida", "avda": "avenida", "al": "alameda", "pca": "praça", "pc": "praça",
+	"tv": "travessa", "trav": "travessa", "rod": "rodovia", "estr": "estrada",
+	"lgo": "largo", "lg": "largo", "pq": "parque", "vl": "vila", "jd": "jardim", "jdm": "jardim",
+	"cj": "conjunto", "res": "residencial", "vd": "viaduto", "bc": "beco", "lad": "ladeira",
+	// Títulos e patentes
+	"dr": "doutor", "dra": "doutora", "prof": "professor", "profa": "professora", "eng": "engenheiro",
+	"cel": "coronel", "gen": "general", "mal": "marechal", "cap": "capitão", "ten": "tenente",
+	"maj": "major", "sgt": "sargento", "alm": "almirante", "brig": "brigadeiro",
+	"pres": "presidente", "gov": "governador", "sen": "senador", "dep": "deputado", "ver": "vereador",
+	"pde": "padre", "fr": "frei", "d": "dom", "sta": "santa", "sto": "santo", "s": "são",
+	"n": "nossa", "sra": "senhora", "nsa": "nossa",
+}
+
+// streetTypes são os tipos de logradouro, ignorados na comparação quando só
+// um dos lados informa ("Paulista" x "Avenida Paulista")
+var streetTypes = map[string]bool{
+	"rua": true, "avenida": true, "alameda": true, "praca": true, "travessa": true, "rodovia": true,
+	"estrada": true, "largo": true, "parque": true, "vila": true, "viela": true, "via": true,
+	"conjunto": true, "residencial": true, "viaduto": true, "beco": true, "ladeira": true,
+	"passarela": true, "quadra": true, "servidao": true, "acesso": true,
+}
+
+// lowercase são as palavras que ficam minúsculas no meio do nome ("Rua XV de Novembro")
+var lowercase = map[string]bool{
+	"a": true, "e": true, "o": true, "da": true, "das": true, "de": true, "do": true, "dos": true,
+	"em": true, "na": true, "nas": true, "no": true, "nos": true,
+}
+
+// words separa o texto em palavras: pontuação vira separador, mas o hífen
+// fica ("Ji-Paraná") e o ponto de abreviação some ("Av." ==> "Av")
+func words(s string) []string {
+	return strings.FieldsFunc(s, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '-' && r != '\''
+	})
+}
+
+// expand troca a abreviação pela palavra completa, se for uma. "s", "n" e "d"
+// só contam antes de outro nome ("S. Paulo", "N. Sra.", "D. Pedro"): no fim
+// são letras de rua ("Rua D").
+func expand(ws []string, i int) string {
+	key := municipios.Fold(ws[i])
+	full, ok := abbreviations[key]
+	if !ok || ((key == "s" || key == "n" || key == "d") && i == len(ws)-1) {
+		return ws[i]
+	}
+	return full
+}
+
+// Format deixa o endereço no padrão de exibição: abreviações expandidas,
+// iniciais maiúsculas, conectivos minúsculos e algarismos romanos e letras de
+// rua em maiúsculas. Ex.: "av. brig. faria lima" ==> "Avenida Brigadeiro Faria Lima",
+// "rua a" ==> "Rua A"
+func Format(s string) string {
+	ws := words(s)
+	out := make([]string, len(ws))
+	for i := range ws {
+		w := strings.ToLower(expand(ws, i))
+		switch {
+		case i > 0 && lowercase[w] && !streetLetter(ws, i):
+			out[i] = w
+		case isRoman(w):
+			out[i] = strings.ToUpper(w)
+		default:
+			out[i] = capitalize(w)
+		}
+	}
+	return strings.Join(out, " ")
+}
+
+// Normalize é a forma usada para comparar: expandida, sem acentos, em minúsculas
+// e sem pontuação. Ex.: "Pça. da Sé" ==> "praca da se"
+func Normalize(s string) string {
+	ws := words(s)
+	out := make([]string, len(ws))
+	for i := range ws {
+		out[i] = municipios.Fold(expand(ws, i))
+	}
+	return strings.Join(out, " ")
+}
+
+// streetLetter diz se a palavra de uma letra é o nome da rua ("Rua A", "Quadra E 2")
+// e não um conectivo: "a", "e" e "o" só são conectivos antes de outro nome
+func streetLetter(ws []string, i int) bool {
+	if utf8.RuneCountInString(ws[i]) != 1 {
+		return false
+	}
+	if i == len(ws)-1 {
+		return true
+	}
+	next := ws[i+1]
+	return utf8.RuneCountInString(next) == 1 || strings.IndexFunc(next, unicode.IsLetter) < 0
+}
+
+// capitalize põe a primeira letra de cada parte em maiúscula ("ji-paraná" ==> "Ji-Paraná")
+func capitalize(w string) string {
+	parts := strings.Split(w, "-")
+	for i, p := range parts {
+		r, size := utf8.DecodeRuneInString(p)
+		parts[i] = string(unicode.ToUpper(r)) + p[size:]
+	}
+	return strings.Join(parts, "-")
+}
+
+// roman reconhece algarismos romanos de 1 a 99, usados em nomes de rua (XV, IX, ...)
+var roman = regexp.MustCompile(`^(xc|xl|l?x{0,3})(ix|iv|v?i{0,3})$`)
+
+func isRoman(w string) bool {
+	return w != "" && roman.MatchString(w)
+}
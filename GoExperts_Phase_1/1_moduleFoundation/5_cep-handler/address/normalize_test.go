@@ -0,0 +1,57 @@
+package address
+
+import "testing"
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		input  string
+		expect string
+	}{
+		{"Av. Paulista", "avenida paulista"},
+		{"avenida   PAULISTA", "avenida paulista"},
+		{"Pça. da Sé", "praca da se"},
+		{"R. Cap. Adelmio Norberto da Silva", "rua capitao adelmio norberto da silva"},
+		{"Av Brig. Faria Lima", "avenida brigadeiro faria lima"},
+		{"R. N. Sra. Aparecida", "rua nossa senhora aparecida"},
+		{"Rua D", "rua d"}, // Letra no fim não é abreviação
+		{"Rua D. Pedro II", "rua dom pedro ii"},
+		{"Est. do Pé de Galinha", "est do pe de galinha"},
+		{"Rua Ji-Paraná, 100", "rua ji-parana 100"},
+		{"", ""},
+	}
+
+	for _, tt := range tests {
+		if got := Normalize(tt.input); got != tt.expect {
+			t.Errorf("Normalize(%q) = %q; expect %q", tt.input, got, tt.expect)
+		}
+	}
+}
+
+func TestFormat(t *testing.T) {
+	tests := []struct {
+		input  string
+		expect string
+	}{
+		{"av. paulista", "Avenida Paulista"},
+		{"RUA XV DE NOVEMBRO", "Rua XV de Novembro"},
+		{"pça. da sé", "Praça da Sé"},
+		{"r. dr. joão da silva e souza", "Rua Doutor João da Silva e Souza"},
+		{"rua d. pedro ii", "Rua Dom Pedro II"},
+		{"Rua Civil", "Rua Civil"}, // Só letras romanas, mas não é número
+		{"al. santos", "Alameda Santos"},
+		{"de ribeirão preto", "De Ribeirão Preto"}, // Conectivo no início fica maiúsculo
+		{"rua a", "Rua A"},                         // Letra de rua, não o conectivo "a"
+		{"QUADRA B", "Quadra B"},
+		{"travessa e 2", "Travessa E 2"},
+		{"rua o", "Rua O"},
+		{"rua joão e maria", "Rua João e Maria"}, // Conectivo entre nomes continua minúsculo
+		{"avenida vi", "Avenida VI"},
+		{"rua xiv de julho", "Rua XIV de Julho"},
+	}
+
+	for _, tt := range tests {
+		if got := Format(tt.input); got != tt.expect {
+			t.Errorf("Format(%q) = %q; expect %q", tt.input, got, tt.expect)
+		}
+	}
+}
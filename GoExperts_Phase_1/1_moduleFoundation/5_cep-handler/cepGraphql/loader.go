@@ -0,0 +1,86 @@
+package cepGraphql
+
+import (
+	"GoProject/1_moduleFoundation/5_cep-handler/getCep"
+	"context"
+	"sync"
+)
+
+// loader busca cada CEP no máximo uma vez por query, mesmo que ele apareça
+// em vários campos (aliases) ou repetido em addresses(ceps:).
+// As buscas rodam em paralelo, limitadas por um semáforo.
+// Vive só durante uma execução: é criado por requisição e guardado no ctx.
+type loader struct {
+	ctx context.Context
+	sem chan struct{}
+
+	mu    sync.Mutex
+	calls map[string]*call // cep normalizado ==> busca
+}
+
+// call é uma busca em andamento ou concluída; done fecha quando termina
+type call struct {
+	done chan struct{}
+	addr *getCep.ViaCEP
+	err  error
+}
+
+type loaderKey struct{}
+
+func newLoader(ctx context.Context, concurrency int) *loader {
+	if concurrency <= 0 {
+		concurrency = 1
+	}
+	return &loader{ctx: ctx, sem: make(chan struct{}, concurrency), calls: make(map[string]*call)}
+}
+
+// withLoader guarda o loader no ctx da execução
+func withLoader(ctx context.Context, l *loader) context.Context {
+	return context.WithValue(ctx, loaderKey{}, l)
+}
+
+// loaderFrom devolve o loader do ctx (ou um novo, se o schema for usado fora do Handler)
+func loaderFrom(ctx context.Context) *loader {
+	if l, ok := ctx.Value(loaderKey{}).(*loader); ok {
+		return l
+	}
+	return newLoader(ctx, defaultConcurrency)
+}
+
+// load inicia a busca do CEP (se ainda não foi iniciada) sem esperar o resultado
+func (l *loader) load(cep string) *call {
+	normalized, err := getCep.Normalize(cep)
+	if err != nil {
+		c := &call{done: make(chan struct{}), err: err}
+		close(c.done)
+		return c
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	c, ok := l.calls[normalized]
+	if !ok {
+		c = &call{done: make(chan struct{})}
+		l.calls[normalized] = c
+		go l.fetch(normalized, c)
+	}
+	return c
+}
+
+func (l *loader) fetch(cep string, c *call) {
+	defer close(c.done)
+	select {
+	case l.sem <- struct{}{}:
+		defer func() { <-l.sem }()
+	case <-l.ctx.Done():
+		c.err = l.ctx.Err()
+		return
+	}
+	c.addr, c.err = getCep.GetCep(l.ctx, cep)
+}
+
+// wait espera a busca terminar
+func (c *call) wait() (*getCep.ViaCEP, error) {
+	<-c.done
+	return c.addr, c.err
+}
@@ -0,0 +1,254 @@
+package cepGraphql
+
+import (
+	"GoProject/1_moduleFoundation/5_cep-handler/getCep"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/gqlerrors"
+)
+
+// Exemplo:
+//
+//	curl localhost:8080/graphql -d '{"query": "{ address(cep: \"01001000\") { cep logradouro uf } }"}'
+//	curl localhost:8080/graphql -d '{"query": "{ addresses(ceps: [\"01001000\", \"14093070\"]) { cep error address { localidade ddd } } }"}'
+
+// defaultConcurrency limita as buscas simultâneas de uma query
+const defaultConcurrency = 10
+
+// maxCEPs limita quantos CEPs uma query pode pedir em addresses(ceps:)
+const maxCEPs = 1000
+
+// addressType expõe os campos da struct getCep.ViaCEP (resolvidos pelas tags json)
+var addressType = graphql.NewObject(graphql.ObjectConfig{
+	Name:        "Address",
+	Description: "Endereço no formato da ViaCEP",
+	Fields: graphql.Fields{
+		"cep":         &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+		"logradouro":  &graphql.Field{Type: graphql.String},
+		"complemento": &graphql.Field{Type: graphql.String},
+		"unidade":     &graphql.Field{Type: graphql.String},
+		"bairro":      &graphql.Field{Type: graphql.String},
+		"localidade":  &graphql.Field{Type: graphql.String},
+		"uf":          &graphql.Field{Type: graphql.String},
+		"estado":      &graphql.Field{Type: graphql.String},
+		"regiao":      &graphql.Field{Type: graphql.String},
+		"ibge":        &graphql.Field{Type: graphql.String},
+		"gia":         &graphql.Field{Type: graphql.String},
+		"ddd":         &graphql.Field{Type: graphql.String},
+		"siafi":       &graphql.Field{Type: graphql.String},
+	},
+})
+
+// addressResult é um item de addresses(ceps:): o erro de um CEP não derruba os outros
+type addressResult struct {
+	Cep     string         `json:"cep"`
+	Address *getCep.ViaCEP `json:"address"`
+	Code    string         `json:"code"`
+	Error   string         `json:"error"`
+}
+
+var addressResultType = graphql.NewObject(graphql.ObjectConfig{
+	Name: "AddressResult",
+	Fields: graphql.Fields{
+		"cep":     &graphql.Field{Type: graphql.NewNonNull(graphql.String), Description: "CEP como foi enviado"},
+		"address": &graphql.Field{Type: addressType, Description: "null quando houve erro"},
+		"code":    &graphql.Field{Type: graphql.String, Description: "INVALID_CEP, NOT_FOUND, UNAVAILABLE, TIMEOUT ou INTERNAL"},
+		"error":   &graphql.Field{Type: graphql.String},
+	},
+})
+
+// NewSchema monta o schema com as queries address(cep:) e addresses(ceps:)
+func NewSchema() (graphql.Schema, error) {
+	query := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Query",
+		Fields: graphql.Fields{
+			"address": &graphql.Field{
+				Type:        addressType,
+				Description: "Busca um CEP (ex.: \"01001000\" ou \"01001-000\")",
+				Args: graphql.FieldConfigArgument{
+					"cep": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.String)},
+				},
+				Resolve: resolveAddress,
+			},
+			"addresses": &graphql.Field{
+				Type:        graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(addressResultType))),
+				Description: "Busca vários CEPs em paralelo; CEPs repetidos são buscados uma vez só",
+				Args: graphql.FieldConfigArgument{
+					"ceps": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(graphql.String)))},
+				},
+				Resolve: resolveAddresses,
+			},
+		},
+	})
+	return graphql.NewSchema(graphql.SchemaConfig{Query: query})
+}
+
+// resolveAddress inicia a busca e devolve um thunk: o executor resolve os
+// outros campos (ex.: aliases de address) antes de esperar, então todas as
+// buscas da query correm em paralelo
+func resolveAddress(p graphql.ResolveParams) (any, error) {
+	cep, _ := p.Args["cep"].(string)
+	c := loaderFrom(p.Context).load(cep)
+	return func() (any, error) {
+		addr, err := c.wait()
+		if err != nil {
+			panic(locatedError(p.Info, &lookupError{err: err}))
+		}
+		return addr, nil
+	}, nil
+}
+
+// locatedError monta o erro com local e caminho do campo. Um erro devolvido
+// por um thunk perde as extensions no graphql-go v0.8.1; com panic de um
+// *gqlerrors.Error o executor usa o erro como está.
+func locatedError(info graphql.ResolveInfo, err error) *gqlerrors.Error {
+	return gqlerrors.NewErrorWithPath(err.Error(), graphql.FieldASTsToNodeASTs(info.FieldASTs), "", nil, nil, info.Path.AsArray(), err)
+}
+
+func resolveAddresses(p graphql.ResolveParams) (any, error) {
+	args, _ := p.Args["ceps"].([]any)
+	if len(args) > maxCEPs {
+		return nil, &lookupError{err: errTooManyCEPs}
+	}
+
+	l := loaderFrom(p.Context)
+	ceps := make([]string, len(args))
+	calls := make([]*call, len(args))
+	for i, arg := range args {
+		ceps[i], _ = arg.(string)
+		calls[i] = l.load(ceps[i]) // Repetidos caem na mesma busca
+	}
+
+	return func() (any, error) {
+		results := make([]addressResult, len(calls))
+		for i, c := range calls {
+			addr, err := c.wait()
+			results[i] = addressResult{Cep: ceps[i], Address: addr}
+			if err != nil {
+				results[i].Code, results[i].Error = errorCode(err), err.Error()
+			}
+		}
+		return results, nil
+	}, nil
+}
+
+var errTooManyCEPs = errors.New("muitos CEPs em uma única query")
+
+// lookupError leva o código do erro em "extensions" na resposta GraphQL
+type lookupError struct {
+	err error
+}
+
+func (e *lookupError) Error() string { return e.err.Error() }
+
+func (e *lookupError) Unwrap() error { return e.err }
+
+func (e *lookupError) Extensions() map[string]any {
+	return map[string]any{"code": errorCode(e.err)}
+}
+
+// errorCode traduz os erros do getCep para os códigos expostos no schema
+func errorCode(err error) string {
+	switch {
+	case errors.Is(err, errTooManyCEPs):
+		return "TOO_MANY_CEPS"
+	case errors.Is(err, getCep.ErrInvalidCEP):
+		return "INVALID_CEP"
+	case errors.Is(err, getCep.ErrCEPNotFound):
+		return "NOT_FOUND"
+	case errors.Is(err, getCep.ErrUpstreamUnavailable), errors.Is(err, getCep.ErrCircuitOpen):
+		return "UNAVAILABLE"
+	case errors.Is(err, context.DeadlineExceeded):
+		return "TIMEOUT"
+	}
+	return "INTERNAL"
+}
+
+// ############################## HANDLER ######################################
+
+// request é o corpo de um POST /graphql
+type request struct {
+	Query         string         `json:"query"`
+	OperationName string         `json:"operationName"`
+	Variables     map[string]any `json:"variables"`
+}
+
+// Handler executa queries via POST (JSON) ou GET (?query=...)
+type Handler struct {
+	Schema      graphql.Schema
+	Timeout     time.Duration // Limite de cada query (0 = sem limite além do ctx da requisição)
+	Concurrency int           // Buscas simultâneas por query (padrão 10)
+}
+
+// NewHandler cria o Handler com o schema padrão
+func NewHandler() (*Handler, error) {
+	schema, err := NewSchema()
+	if err != nil {
+		return nil, err
+	}
+	return &Handler{Schema: schema, Timeout: 10 * time.Second, Concurrency: defaultConcurrency}, nil
+}
+
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var req request
+	switch r.Method {
+	case http.MethodGet:
+		req.Query = r.URL.Query().Get("query")
+		req.OperationName = r.URL.Query().Get("operationName")
+		if vars := r.URL.Query().Get("variables"); vars != "" {
+			if err := json.Unmarshal([]byte(vars), &req.Variables); err != nil {
+				writeError(w, http.StatusBadRequest, "variables deve ser um objeto JSON")
+				return
+			}
+		}
+	case http.MethodPost:
+		r.Body = http.MaxBytesReader(w, r.Body, 1<<20) // 1MB
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			writeError(w, http.StatusBadRequest, "o corpo deve ser um JSON com o campo query")
+			return
+		}
+	default:
+		w.Header().Set("Allow", "GET, POST")
+		writeError(w, http.StatusMethodNotAllowed, "use GET ou POST")
+		return
+	}
+	if req.Query == "" {
+		writeError(w, http.StatusBadRequest, "query vazia")
+		return
+	}
+
+	ctx := r.Context()
+	if h.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, h.Timeout)
+		defer cancel()
+	}
+	concurrency := h.Concurrency
+	if concurrency <= 0 {
+		concurrency = defaultConcurrency
+	}
+	ctx = withLoader(ctx, newLoader(ctx, concurrency))
+
+	result := graphql.Do(graphql.Params{
+		Schema:         h.Schema,
+		RequestString:  req.Query,
+		VariableValues: req.Variables,
+		OperationName:  req.OperationName,
+		Context:        ctx,
+	})
+
+	// Como no GraphQL em geral, erros de campo vão no corpo com status 200
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(result)
+}
+
+func writeError(w http.ResponseWriter, status int, msg string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]any{"errors": []map[string]string{{"message": msg}}})
+}
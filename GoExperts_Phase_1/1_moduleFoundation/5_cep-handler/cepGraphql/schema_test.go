@@ -0,0 +1,200 @@
+package cepGraphql
+
+import (
+	"GoProject/1_moduleFoundation/5_cep-handler/getCep"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// countingProvider conta as buscas por CEP e mede quantas rodaram ao mesmo tempo
+type countingProvider struct {
+	delay time.Duration
+
+	mu      sync.Mutex
+	calls   map[string]int
+	running atomic.Int32
+	peak    atomic.Int32
+}
+
+func (p *countingProvider) Name() string { return "counting" }
+
+func (p *countingProvider) Lookup(ctx context.Context, cep string) (*getCep.ViaCEP, error) {
+	p.mu.Lock()
+	p.calls[cep]++
+	p.mu.Unlock()
+
+	running := p.running.Add(1)
+	defer p.running.Add(-1)
+	for {
+		peak := p.peak.Load()
+		if running <= peak || p.peak.CompareAndSwap(peak, running) {
+			break
+		}
+	}
+	time.Sleep(p.delay)
+
+	if cep == "01009999" {
+		return nil, getCep.ErrCEPNotFound
+	}
+	return &getCep.ViaCEP{Cep: cep[:5] + "-" + cep[5:], Logradouro: "Rua " + cep, Uf: "SP", Ddd: "11"}, nil
+}
+
+func useCountingProvider(t *testing.T, delay time.Duration) *countingProvider {
+	t.Helper()
+	p := &countingProvider{delay: delay, calls: make(map[string]int)}
+	previous := getCep.DefaultProvider
+	getCep.DefaultProvider = p
+	t.Cleanup(func() { getCep.DefaultProvider = previous })
+	return p
+}
+
+// graphqlResponse é o formato da resposta do Handler
+type graphqlResponse struct {
+	Data   map[string]json.RawMessage `json:"data"`
+	Errors []struct {
+		Message    string         `json:"message"`
+		Extensions map[string]any `json:"extensions"`
+	} `json:"errors"`
+}
+
+func execute(t *testing.T, query string) graphqlResponse {
+	t.Helper()
+	h, err := NewHandler()
+	if err != nil {
+		t.Fatalf("NewHandler() erro inesperado: %v", err)
+	}
+	body, _ := json.Marshal(map[string]string{"query": query})
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/graphql", strings.NewReader(string(body))))
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d; expect 200 (%s)", rec.Code, rec.Body.String())
+	}
+	var resp graphqlResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("resposta inválida: %v (%s)", err, rec.Body.String())
+	}
+	return resp
+}
+
+func TestAddressSelectsFields(t *testing.T) {
+	useCountingProvider(t, 0)
+
+	resp := execute(t, `{ address(cep: "01001-000") { cep uf } }`)
+	if len(resp.Errors) > 0 {
+		t.Fatalf("erros inesperados: %+v", resp.Errors)
+	}
+	if got := string(resp.Data["address"]); got != `{"cep":"01001-000","uf":"SP"}` {
+		t.Errorf("address = %s; expect só cep e uf", got)
+	}
+}
+
+func TestAddressErrors(t *testing.T) {
+	useCountingProvider(t, 0)
+
+	tests := []struct {
+		cep  string
+		code string
+	}{
+		{"abc", "INVALID_CEP"},
+		{"01009999", "NOT_FOUND"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.cep, func(t *testing.T) {
+			resp := execute(t, `{ address(cep: "`+tt.cep+`") { cep } }`)
+			if string(resp.Data["address"]) != "null" {
+				t.Errorf("address = %s; expect null", resp.Data["address"])
+			}
+			if len(resp.Errors) != 1 || resp.Errors[0].Extensions["code"] != tt.code {
+				t.Errorf("errors = %+v; expect code %s", resp.Errors, tt.code)
+			}
+		})
+	}
+}
+
+func TestAddressesDedupAndConcurrency(t *testing.T) {
+	p := useCountingProvider(t, 50*time.Millisecond)
+
+	start := time.Now()
+	resp := execute(t, `{
+		a: address(cep: "14093070") { cep }
+		b: address(cep: "14093-070") { cep }
+		list: addresses(ceps: ["01001000", "01001-000", "20040020", "01009999", "abc", "14093070"]) {
+			cep code address { logradouro }
+		}
+	}`)
+	elapsed := time.Since(start)
+
+	if len(resp.Errors) > 0 {
+		t.Fatalf("erros inesperados: %+v", resp.Errors)
+	}
+
+	// Cada CEP distinto é buscado uma única vez na query inteira
+	expectCalls := map[string]int{"14093070": 1, "01001000": 1, "20040020": 1, "01009999": 1}
+	for cep, n := range expectCalls {
+		if p.calls[cep] != n {
+			t.Errorf("buscas de %s = %d; expect %d", cep, p.calls[cep], n)
+		}
+	}
+	if len(p.calls) != len(expectCalls) {
+		t.Errorf("CEPs buscados = %v; expect %v", p.calls, expectCalls)
+	}
+
+	// 4 buscas de 50ms em paralelo, não em série
+	if p.peak.Load() < 2 || elapsed > 150*time.Millisecond {
+		t.Errorf("pico de buscas simultâneas = %d em %v; expect paralelo", p.peak.Load(), elapsed)
+	}
+
+	var list []struct {
+		Cep     string `json:"cep"`
+		Code    string `json:"code"`
+		Address *struct {
+			Logradouro string `json:"logradouro"`
+		} `json:"address"`
+	}
+	json.Unmarshal(resp.Data["list"], &list)
+	expectCodes := []string{"", "", "", "NOT_FOUND", "INVALID_CEP", ""}
+	if len(list) != len(expectCodes) {
+		t.Fatalf("addresses = %d itens; expect %d", len(list), len(expectCodes))
+	}
+	for i, code := range expectCodes {
+		if list[i].Code != code {
+			t.Errorf("addresses[%d] (%s) code = %q; expect %q", i, list[i].Cep, list[i].Code, code)
+		}
+		if (code == "") != (list[i].Address != nil) {
+			t.Errorf("addresses[%d] (%s) address = %v; expect preenchido só sem erro", i, list[i].Cep, list[i].Address)
+		}
+	}
+}
+
+func TestHandlerBadRequest(t *testing.T) {
+	h, _ := NewHandler()
+
+	tests := []struct {
+		name   string
+		req    *http.Request
+		status int
+	}{
+		{"corpo inválido", httptest.NewRequest(http.MethodPost, "/graphql", strings.NewReader("{")), http.StatusBadRequest},
+		{"query vazia", httptest.NewRequest(http.MethodGet, "/graphql", nil), http.StatusBadRequest},
+		{"método", httptest.NewRequest(http.MethodDelete, "/graphql", nil), http.StatusMethodNotAllowed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, tt.req)
+			if rec.Code != tt.status {
+				t.Errorf("status = %d; expect %d", rec.Code, tt.status)
+			}
+		})
+	}
+}
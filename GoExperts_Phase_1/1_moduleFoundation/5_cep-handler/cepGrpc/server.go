@@ -0,0 +1,162 @@
+package cepGrpc
+
+import (
+	"GoProject/1_moduleFoundation/5_cep-handler/getCep"
+	"GoProject/1_moduleFoundation/5_cep-handler/pb"
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Server implementa o pb.AddressService com o getCep.GetCep, a mesma busca
+// do BuscaCepHandler (DefaultProvider com cache, breaker, ...).
+// O deadline do cliente gRPC chega pelo ctx e vale para todas as buscas.
+type Server struct {
+	pb.UnimplementedAddressServiceServer
+
+	Timeout     time.Duration // Limite por requisição, além do deadline do cliente (padrão 10s)
+	Concurrency int           // Buscas simultâneas no GetAddresses (padrão 10)
+	MaxBatch    int           // Máximo de CEPs por GetAddresses (padrão 1000)
+
+	// Stale, se informado, é usado quando o circuito está aberto para
+	// responder com um endereço antigo do cache (como no handler HTTP)
+	Stale func(cep string) (*getCep.ViaCEP, bool)
+}
+
+// NewServer cria um Server com os valores padrão
+func NewServer() *Server {
+	return &Server{Timeout: 10 * time.Second, Concurrency: 10, MaxBatch: 1000}
+}
+
+func (s *Server) GetAddress(ctx context.Context, req *pb.GetAddressRequest) (*pb.Address, error) {
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	addr, err := s.lookup(ctx, req.GetCep())
+	if err != nil {
+		return nil, ToStatus(err)
+	}
+	return toProto(addr), nil
+}
+
+func (s *Server) GetAddresses(req *pb.GetAddressesRequest, stream pb.AddressService_GetAddressesServer) error {
+	ceps := req.GetCeps()
+	if limit := s.maxBatch(); len(ceps) > limit {
+		return status.Errorf(codes.InvalidArgument, "máximo de %d CEPs por requisição", limit)
+	}
+
+	ctx, cancel := s.withTimeout(stream.Context())
+	defer cancel()
+
+	// stream.Send não pode ser chamado em paralelo
+	var mu sync.Mutex
+	var sendErr error
+	getCep.LookupEach(ctx, ceps, s.Concurrency, func(i int, res getCep.BatchResult) {
+		if errors.Is(res.Err, getCep.ErrCircuitOpen) && s.Stale != nil {
+			res.Address, res.Err = s.stale(res.Cep, res.Err)
+		}
+		result := &pb.AddressResult{Index: int32(i), Cep: res.Cep}
+		if res.Err != nil {
+			st := status.Convert(ToStatus(res.Err))
+			result.Code, result.Error = uint32(st.Code()), st.Message()
+		} else {
+			result.Address = toProto(res.Address)
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+		if sendErr != nil {
+			return
+		}
+		if sendErr = stream.Send(result); sendErr != nil {
+			cancel() // Cliente foi embora: para as buscas que faltam
+		}
+	})
+	return sendErr // Deadline estourado no meio do lote aparece no erro de cada CEP
+}
+
+// lookup busca o CEP e, com o circuito aberto, tenta o endereço antigo do cache
+func (s *Server) lookup(ctx context.Context, cep string) (*getCep.ViaCEP, error) {
+	addr, err := getCep.GetCep(ctx, cep)
+	if errors.Is(err, getCep.ErrCircuitOpen) && s.Stale != nil {
+		return s.stale(cep, err)
+	}
+	return addr, err
+}
+
+func (s *Server) stale(cep string, err error) (*getCep.ViaCEP, error) {
+	normalized, nerr := getCep.Normalize(cep)
+	if nerr != nil {
+		return nil, err
+	}
+	if addr, ok := s.Stale(normalized); ok {
+		return addr, nil
+	}
+	return nil, err
+}
+
+func (s *Server) withTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	if s.Timeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, s.Timeout) // Vale o menor entre este e o deadline do cliente
+}
+
+func (s *Server) maxBatch() int {
+	if s.MaxBatch <= 0 {
+		return 1000
+	}
+	return s.MaxBatch
+}
+
+// ToStatus converte os erros do getCep em status gRPC (nil continua nil):
+//
+//	ErrInvalidCEP                         ==> INVALID_ARGUMENT
+//	ErrCEPNotFound                        ==> NOT_FOUND
+//	ErrUpstreamUnavailable/ErrCircuitOpen ==> UNAVAILABLE
+//	context.DeadlineExceeded              ==> DEADLINE_EXCEEDED
+//	context.Canceled                      ==> CANCELED
+func ToStatus(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	code := codes.Internal
+	switch {
+	case errors.Is(err, getCep.ErrInvalidCEP):
+		code = codes.InvalidArgument
+	case errors.Is(err, getCep.ErrCEPNotFound):
+		code = codes.NotFound
+	case errors.Is(err, getCep.ErrUpstreamUnavailable), errors.Is(err, getCep.ErrCircuitOpen):
+		code = codes.Unavailable
+	case errors.Is(err, context.DeadlineExceeded):
+		code = codes.DeadlineExceeded
+	case errors.Is(err, context.Canceled):
+		code = codes.Canceled
+	}
+	return status.Error(code, err.Error())
+}
+
+// FromProto converte o pb.Address de volta para a struct usada no resto do projeto
+func FromProto(a *pb.Address) *getCep.ViaCEP {
+	if a == nil {
+		return nil
+	}
+	return &getCep.ViaCEP{
+		Cep: a.Cep, Logradouro: a.Logradouro, Complemento: a.Complemento, Unidade: a.Unidade,
+		Bairro: a.Bairro, Localidade: a.Localidade, Uf: a.Uf, Estado: a.Estado, Regiao: a.Regiao,
+		Ibge: a.Ibge, Gia: a.Gia, Ddd: a.Ddd, Siafi: a.Siafi,
+	}
+}
+
+func toProto(a *getCep.ViaCEP) *pb.Address {
+	return &pb.Address{
+		Cep: a.Cep, Logradouro: a.Logradouro, Complemento: a.Complemento, Unidade: a.Unidade,
+		Bairro: a.Bairro, Localidade: a.Localidade, Uf: a.Uf, Estado: a.Estado, Regiao: a.Regiao,
+		Ibge: a.Ibge, Gia: a.Gia, Ddd: a.Ddd, Siafi: a.Siafi,
+	}
+}
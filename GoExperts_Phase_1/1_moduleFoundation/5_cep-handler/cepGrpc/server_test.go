@@ -0,0 +1,212 @@
+package cepGrpc
+
+import (
+	"GoProject/1_moduleFoundation/5_cep-handler/getCep"
+	"GoProject/1_moduleFoundation/5_cep-handler/pb"
+	"context"
+	"errors"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// stubProvider responde a partir de um map; CEPs em errs devolvem o erro e delay atrasa tudo
+type stubProvider struct {
+	addrs map[string]*getCep.ViaCEP
+	errs  map[string]error
+	delay time.Duration
+}
+
+func (p *stubProvider) Name() string { return "stub" }
+
+func (p *stubProvider) Lookup(ctx context.Context, cep string) (*getCep.ViaCEP, error) {
+	select {
+	case <-time.After(p.delay):
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	if err, ok := p.errs[cep]; ok {
+		return nil, err
+	}
+	if addr, ok := p.addrs[cep]; ok {
+		return addr, nil
+	}
+	return nil, getCep.ErrCEPNotFound
+}
+
+func newStub() *stubProvider {
+	return &stubProvider{
+		addrs: map[string]*getCep.ViaCEP{
+			"01001000": {Cep: "01001-000", Logradouro: "Praça da Sé", Localidade: "São Paulo", Uf: "SP"},
+			"14093070": {Cep: "14093-070", Localidade: "Ribeirão Preto", Uf: "SP"},
+		},
+		errs: map[string]error{
+			"20040020": getCep.ErrUpstreamUnavailable,
+			"30130010": &getCep.CircuitOpenError{RetryAfter: time.Second},
+		},
+	}
+}
+
+// newTestClient sobe o Server em um bufconn (sem rede) com o provider informado
+func newTestClient(t *testing.T, provider getCep.Provider, srv *Server) pb.AddressServiceClient {
+	t.Helper()
+	previous := getCep.DefaultProvider
+	getCep.DefaultProvider = provider
+	t.Cleanup(func() { getCep.DefaultProvider = previous })
+
+	listener := bufconn.Listen(1 << 20)
+	server := grpc.NewServer()
+	pb.RegisterAddressServiceServer(server, srv)
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return listener.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return pb.NewAddressServiceClient(conn)
+}
+
+func TestGetAddress(t *testing.T) {
+	client := newTestClient(t, newStub(), NewServer())
+
+	tests := []struct {
+		cep    string
+		code   codes.Code
+		expect string // Logradouro
+	}{
+		{"01001-000", codes.OK, "Praça da Sé"},
+		{"abc", codes.InvalidArgument, ""},
+		{"01009999", codes.NotFound, ""},
+		{"20040020", codes.Unavailable, ""},
+		{"30130010", codes.Unavailable, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.cep, func(t *testing.T) {
+			addr, err := client.GetAddress(context.Background(), &pb.GetAddressRequest{Cep: tt.cep})
+			if got := status.Code(err); got != tt.code {
+				t.Fatalf("GetAddress(%q) código = %s; expect %s (%v)", tt.cep, got, tt.code, err)
+			}
+			if tt.code == codes.OK && addr.GetLogradouro() != tt.expect {
+				t.Errorf("GetAddress(%q).Logradouro = %q; expect %q", tt.cep, addr.GetLogradouro(), tt.expect)
+			}
+		})
+	}
+}
+
+func TestGetAddressDeadline(t *testing.T) {
+	stub := newStub()
+	stub.delay = time.Second
+	client := newTestClient(t, stub, NewServer())
+
+	// O deadline do cliente chega ao provider pelo ctx do servidor
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	_, err := client.GetAddress(ctx, &pb.GetAddressRequest{Cep: "01001000"})
+	if status.Code(err) != codes.DeadlineExceeded {
+		t.Fatalf("GetAddress() erro = %v; expect DEADLINE_EXCEEDED", err)
+	}
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("GetAddress() levou %v; o deadline não foi respeitado", elapsed)
+	}
+}
+
+func TestGetAddressStale(t *testing.T) {
+	srv := NewServer()
+	srv.Stale = func(cep string) (*getCep.ViaCEP, bool) {
+		return &getCep.ViaCEP{Cep: "30130-010", Localidade: "Belo Horizonte"}, cep == "30130010"
+	}
+	client := newTestClient(t, newStub(), srv)
+
+	addr, err := client.GetAddress(context.Background(), &pb.GetAddressRequest{Cep: "30130-010"})
+	if err != nil {
+		t.Fatalf("GetAddress() erro inesperado: %v", err)
+	}
+	if addr.GetLocalidade() != "Belo Horizonte" {
+		t.Errorf("GetAddress() = %v; expect endereço do cache", addr)
+	}
+}
+
+func TestGetAddresses(t *testing.T) {
+	client := newTestClient(t, newStub(), NewServer())
+	ceps := []string{"01001000", "abc", "14093070", "01009999", "20040020"}
+
+	stream, err := client.GetAddresses(context.Background(), &pb.GetAddressesRequest{Ceps: ceps})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	codesByIndex := map[int32]codes.Code{}
+	for {
+		res, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			t.Fatalf("Recv() erro inesperado: %v", err)
+		}
+		if res.GetCep() != ceps[res.GetIndex()] {
+			t.Errorf("resultado %d com CEP %q; expect %q", res.GetIndex(), res.GetCep(), ceps[res.GetIndex()])
+		}
+		codesByIndex[res.GetIndex()] = codes.Code(res.GetCode())
+	}
+
+	expect := []codes.Code{codes.OK, codes.InvalidArgument, codes.OK, codes.NotFound, codes.Unavailable}
+	if len(codesByIndex) != len(expect) {
+		t.Fatalf("recebidos %d resultados; expect %d", len(codesByIndex), len(expect))
+	}
+	for i, code := range expect {
+		if codesByIndex[int32(i)] != code {
+			t.Errorf("resultado %d (%s) código = %s; expect %s", i, ceps[i], codesByIndex[int32(i)], code)
+		}
+	}
+}
+
+func TestGetAddressesTooMany(t *testing.T) {
+	srv := NewServer()
+	srv.MaxBatch = 2
+	client := newTestClient(t, newStub(), srv)
+
+	stream, err := client.GetAddresses(context.Background(), &pb.GetAddressesRequest{Ceps: []string{"01001000", "01001000", "01001000"}})
+	if err == nil {
+		_, err = stream.Recv()
+	}
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("GetAddresses() erro = %v; expect INVALID_ARGUMENT", err)
+	}
+}
+
+func TestToStatus(t *testing.T) {
+	tests := []struct {
+		err  error
+		code codes.Code
+	}{
+		{nil, codes.OK},
+		{getCep.ErrInvalidCEP, codes.InvalidArgument},
+		{getCep.ErrCEPNotFound, codes.NotFound},
+		{getCep.ErrUpstreamUnavailable, codes.Unavailable},
+		{&getCep.CircuitOpenError{}, codes.Unavailable},
+		{context.DeadlineExceeded, codes.DeadlineExceeded},
+		{context.Canceled, codes.Canceled},
+		{errors.New("outro"), codes.Internal},
+	}
+
+	for _, tt := range tests {
+		if got := status.Code(ToStatus(tt.err)); got != tt.code {
+			t.Errorf("ToStatus(%v) = %s; expect %s", tt.err, got, tt.code)
+		}
+	}
+}
@@ -0,0 +1,303 @@
+package cepStore
+
+import (
+	"GoProject/1_moduleFoundation/5_cep-handler/getCep"
+	"context"
+	"errors"
+	"log"
+	"sync"
+	"time"
+
+	"gorm.io/driver/mysql"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// DefaultDSN aponta para o banco goexpert do docker-compose.yaml
+// DSN: <user>:<password>@tcp(<host>:<port>)/<dbname>?charset=utf8mb4&parseTime=True&loc=Local
+const DefaultDSN = "myuser:root@tcp(localhost:3306)/goexpert?charset=utf8mb4&parseTime=True&loc=Local"
+
+// Address é o último endereço conhecido de um CEP (tabela addresses).
+// A chave é o CEP sem máscara ("01001000").
+type Address struct {
+	Cep         string `gorm:"type:char(8);primaryKey"`
+	Logradouro  string `gorm:"type:varchar(255)"`
+	Complemento string `gorm:"type:varchar(255)"`
+	Unidade     string `gorm:"type:varchar(100)"`
+	Bairro      string `gorm:"type:varchar(100)"`
+	Localidade  string `gorm:"type:varchar(100)"`
+	Uf          string `gorm:"type:char(2)"`
+	Estado      string `gorm:"type:varchar(50)"`
+	Regiao      string `gorm:"type:varchar(20)"`
+	Ibge        string `gorm:"type:varchar(7)"`
+	Gia         string `gorm:"type:varchar(10)"`
+	Ddd         string `gorm:"type:varchar(2)"`
+	Siafi       string `gorm:"type:varchar(10)"`
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+// Lookup registra quem buscou qual CEP, quando e com que resultado (tabela lookups)
+type Lookup struct {
+	ID         uint      `gorm:"primaryKey" json:"-"`
+	Cep        string    `gorm:"type:char(8);index:idx_lookups_cep_time,priority:1;not null" json:"cep"`
+	Client     string    `gorm:"type:varchar(100);not null" json:"client"`
+	Status     string    `gorm:"type:varchar(20);not null;default:found" json:"status"` // StatusFound, StatusNotFound, ...
+	Error      string    `gorm:"type:varchar(255)" json:"error,omitempty"`              // Só nas buscas que falharam
+	LookedUpAt time.Time `gorm:"index:idx_lookups_cep_time,priority:2;not null" json:"looked_up_at"`
+}
+
+// Resultados de uma busca (coluna status da tabela lookups)
+const (
+	StatusFound    = "found"
+	StatusNotFound = "not_found"
+	StatusInvalid  = "invalid"
+	StatusFailed   = "failed" // Upstream indisponível, circuito aberto, timeout...
+)
+
+// lookupStatus converte o erro da busca no status gravado
+func lookupStatus(err error) string {
+	switch {
+	case err == nil:
+		return StatusFound
+	case errors.Is(err, getCep.ErrCEPNotFound):
+		return StatusNotFound
+	case errors.Is(err, getCep.ErrInvalidCEP):
+		return StatusInvalid
+	}
+	return StatusFailed
+}
+
+// Store grava os endereços e o histórico de buscas usando GORM
+type Store struct {
+	db  *gorm.DB
+	now func() time.Time
+}
+
+// Open conecta no MySQL (ex.: DefaultDSN)
+func Open(dsn string) (*gorm.DB, error) {
+	return gorm.Open(mysql.Open(dsn), &gorm.Config{})
+}
+
+// NewStore cria uma nova instância de Store
+func NewStore(db *gorm.DB) *Store {
+	return &Store{db: db, now: time.Now}
+}
+
+// Migrate garante que as tabelas existam e tenham as colunas corretas
+func (s *Store) Migrate() error {
+	return s.db.AutoMigrate(&Address{}, &Lookup{})
+}
+
+// SaveLookup faz upsert do endereço (chave = CEP) e registra a busca no histórico
+func (s *Store) SaveLookup(ctx context.Context, addr *getCep.ViaCEP, client string) error {
+	cep, err := getCep.Normalize(addr.Cep)
+	if err != nil {
+		return err
+	}
+	if client == "" {
+		client = "desconhecido"
+	}
+
+	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// INSERT ... ON DUPLICATE KEY UPDATE (MySQL) / ON CONFLICT DO UPDATE (SQLite)
+		address := newAddress(cep, addr)
+		err := tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "cep"}},
+			DoUpdates: clause.AssignmentColumns(updatableColumns),
+		}).Create(&address).Error
+		if err != nil {
+			return err
+		}
+		return tx.Create(&Lookup{Cep: cep, Client: client, Status: StatusFound, LookedUpAt: s.now()}).Error
+	})
+}
+
+// SaveFailedLookup registra no histórico uma busca que falhou (não encontrado,
+// upstream fora...), com o status e a mensagem de lookupErr. CEPs em formato
+// inválido não são gravados: não têm como aparecer no histórico de um CEP.
+func (s *Store) SaveFailedLookup(ctx context.Context, cep, client string, lookupErr error) error {
+	normalized, err := getCep.Normalize(cep)
+	if err != nil {
+		return nil
+	}
+	if client == "" {
+		client = "desconhecido"
+	}
+	msg := lookupErr.Error()
+	if runes := []rune(msg); len(runes) > 255 { // Tamanho da coluna error
+		msg = string(runes[:255])
+	}
+	return s.db.WithContext(ctx).Create(&Lookup{
+		Cep:        normalized,
+		Client:     client,
+		Status:     lookupStatus(lookupErr),
+		Error:      msg,
+		LookedUpAt: s.now(),
+	}).Error
+}
+
+// History devolve as buscas de um CEP, da mais recente para a mais antiga
+func (s *Store) History(ctx context.Context, cep string, limit int) ([]Lookup, error) {
+	normalized, err := getCep.Normalize(cep)
+	if err != nil {
+		return nil, err
+	}
+
+	var lookups []Lookup
+	err = s.db.WithContext(ctx).
+		Where("cep = ?", normalized).
+		Order("looked_up_at DESC").Order("id DESC").
+		Limit(limit).
+		Find(&lookups).Error
+	return lookups, err
+}
+
+// FindAddress devolve o endereço salvo de um CEP (gorm.ErrRecordNotFound se não existir)
+func (s *Store) FindAddress(ctx context.Context, cep string) (*Address, error) {
+	normalized, err := getCep.Normalize(cep)
+	if err != nil {
+		return nil, err
+	}
+
+	var address Address
+	if err := s.db.WithContext(ctx).First(&address, "cep = ?", normalized).Error; err != nil {
+		return nil, err
+	}
+	return &address, nil
+}
+
+// updatableColumns são as colunas atualizadas quando o CEP já existe
+var updatableColumns = []string{
+	"logradouro", "complemento", "unidade", "bairro", "localidade", "uf",
+	"estado", "regiao", "ibge", "gia", "ddd", "siafi", "updated_at",
+}
+
+// newAddress converte a struct ViaCEP para o modelo do banco
+func newAddress(cep string, v *getCep.ViaCEP) Address {
+	return Address{
+		Cep:         cep,
+		Logradouro:  v.Logradouro,
+		Complemento: v.Complemento,
+		Unidade:     v.Unidade,
+		Bairro:      v.Bairro,
+		Localidade:  v.Localidade,
+		Uf:          v.Uf,
+		Estado:      v.Estado,
+		Regiao:      v.Regiao,
+		Ibge:        v.Ibge,
+		Gia:         v.Gia,
+		Ddd:         v.Ddd,
+		Siafi:       v.Siafi,
+	}
+}
+
+// ############################## PROVIDER #####################################
+
+type clientKey struct{}
+
+// WithClient guarda no context quem está fazendo a busca (IP, API key, ...)
+func WithClient(ctx context.Context, client string) context.Context {
+	return context.WithValue(ctx, clientKey{}, client)
+}
+
+// ClientFromContext devolve o client guardado por WithClient
+func ClientFromContext(ctx context.Context) string {
+	client, _ := ctx.Value(clientKey{}).(string)
+	return client
+}
+
+// recordingQueue é quantas gravações podem esperar pelo banco. Com a fila cheia
+// a busca não espera: a gravação é descartada e logada.
+const recordingQueue = 1000
+
+// record é uma busca esperando para ser gravada: addr quando deu certo, err quando falhou
+type record struct {
+	cep    string
+	addr   *getCep.ViaCEP
+	err    error
+	client string
+}
+
+// RecordingProvider grava cada busca feita ao upstream, com sucesso ou não
+// (só cancelamentos pelo cliente ficam de fora). A gravação
+// roda num worker em segundo plano: a busca não espera pelo banco, nem quando
+// é respondida pelo cache.
+type RecordingProvider struct {
+	upstream getCep.Provider
+	store    *Store
+
+	mu     sync.RWMutex // Protege closed contra envios durante o Close
+	closed bool
+	queue  chan record
+	done   chan struct{}
+}
+
+// Recording devolve um Provider que grava no Store cada busca (o endereço
+// resolvido ou o erro), junto com o client do context. Falhas ao gravar só são
+// logadas: a busca não falha por causa do banco. Chame Close no desligamento
+// para gravar o que ainda está na fila.
+func (s *Store) Recording(upstream getCep.Provider) *RecordingProvider {
+	p := &RecordingProvider{
+		upstream: upstream,
+		store:    s,
+		queue:    make(chan record, recordingQueue),
+		done:     make(chan struct{}),
+	}
+	go p.worker()
+	return p
+}
+
+func (p *RecordingProvider) Name() string { return "store(" + p.upstream.Name() + ")" }
+
+func (p *RecordingProvider) Lookup(ctx context.Context, cep string) (*getCep.ViaCEP, error) {
+	addr, err := p.upstream.Lookup(ctx, cep)
+	// Cancelamento não é resultado da busca: o cliente só desistiu
+	if errors.Is(err, context.Canceled) {
+		return nil, err
+	}
+
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	if !p.closed {
+		select {
+		case p.queue <- record{cep: cep, addr: addr, err: err, client: ClientFromContext(ctx)}:
+		default:
+			log.Printf("Fila de gravação cheia: busca do CEP %s não registrada", cep)
+		}
+	}
+	return addr, err
+}
+
+// worker grava as buscas da fila, uma de cada vez
+func (p *RecordingProvider) worker() {
+	defer close(p.done)
+	for r := range p.queue {
+		var err error
+		if r.err != nil {
+			err = p.store.SaveFailedLookup(context.Background(), r.cep, r.client, r.err)
+		} else {
+			err = p.store.SaveLookup(context.Background(), r.addr, r.client)
+		}
+		if err != nil {
+			log.Printf("Erro ao gravar busca do CEP %s: %v", r.cep, err)
+		}
+	}
+}
+
+// Close para de aceitar gravações e espera a fila esvaziar ou o ctx acabar
+func (p *RecordingProvider) Close(ctx context.Context) error {
+	p.mu.Lock()
+	if !p.closed {
+		p.closed = true
+		close(p.queue)
+	}
+	p.mu.Unlock()
+
+	select {
+	case <-p.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
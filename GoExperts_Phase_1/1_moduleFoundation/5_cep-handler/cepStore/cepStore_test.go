@@ -0,0 +1,176 @@
+package cepStore
+
+import (
+	"GoProject/1_moduleFoundation/5_cep-handler/getCep"
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// newTestStore cria um Store sobre SQLite em memória (sem MySQL)
+func newTestStore(t *testing.T) *Store {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{Logger: logger.Discard})
+	if err != nil {
+		t.Fatalf("Erro ao abrir SQLite: %v", err)
+	}
+	// ":memory:" é um banco por conexão: uma conexão só para todos verem as mesmas tabelas
+	sqlDB, _ := db.DB()
+	sqlDB.SetMaxOpenConns(1)
+	t.Cleanup(func() { sqlDB.Close() })
+
+	store := NewStore(db)
+	if err := store.Migrate(); err != nil {
+		t.Fatalf("Erro ao migrar tabelas: %v", err)
+	}
+	return store
+}
+
+func TestSaveLookupUpsert(t *testing.T) {
+	store := newTestStore(t)
+	ctx := context.Background()
+
+	first := &getCep.ViaCEP{Cep: "01001-000", Logradouro: "Praça da Sé", Localidade: "São Paulo", Uf: "SP"}
+	if err := store.SaveLookup(ctx, first, "10.0.0.1"); err != nil {
+		t.Fatalf("SaveLookup() erro inesperado: %v", err)
+	}
+	second := &getCep.ViaCEP{Cep: "01001000", Logradouro: "Praça da Sé - lado ímpar", Localidade: "São Paulo", Uf: "SP"}
+	if err := store.SaveLookup(ctx, second, "10.0.0.2"); err != nil {
+		t.Fatalf("SaveLookup() erro inesperado: %v", err)
+	}
+
+	var count int64
+	store.db.Model(&Address{}).Count(&count)
+	if count != 1 {
+		t.Errorf("addresses = %d linhas; expect 1 (upsert pelo CEP)", count)
+	}
+
+	address, err := store.FindAddress(ctx, "01001-000")
+	if err != nil {
+		t.Fatalf("FindAddress() erro inesperado: %v", err)
+	}
+	if address.Logradouro != second.Logradouro {
+		t.Errorf("Logradouro = %q; expect %q (último valor)", address.Logradouro, second.Logradouro)
+	}
+
+	if _, err := store.FindAddress(ctx, "14093070"); !errors.Is(err, gorm.ErrRecordNotFound) {
+		t.Errorf("FindAddress() erro = %v; expect %v", err, gorm.ErrRecordNotFound)
+	}
+}
+
+func TestHistory(t *testing.T) {
+	store := newTestStore(t)
+	ctx := context.Background()
+
+	clock := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	store.now = func() time.Time { return clock }
+
+	addr := &getCep.ViaCEP{Cep: "01001-000", Localidade: "São Paulo"}
+	for _, client := range []string{"ana", "bia", "caio"} {
+		store.SaveLookup(ctx, addr, client)
+		clock = clock.Add(time.Minute)
+	}
+	store.SaveLookup(ctx, &getCep.ViaCEP{Cep: "14093-070"}, "outro")
+
+	history, err := store.History(ctx, "01001000", 2)
+	if err != nil {
+		t.Fatalf("History() erro inesperado: %v", err)
+	}
+	if len(history) != 2 || history[0].Client != "caio" || history[1].Client != "bia" {
+		t.Errorf("History() = %+v; expect caio e bia (mais recentes primeiro)", history)
+	}
+
+	if _, err := store.History(ctx, "abc", 10); !errors.Is(err, getCep.ErrInvalidCEP) {
+		t.Errorf("History() erro = %v; expect %v", err, getCep.ErrInvalidCEP)
+	}
+}
+
+// fixedProvider devolve sempre o mesmo endereço
+type fixedProvider struct{ addr *getCep.ViaCEP }
+
+func (p fixedProvider) Name() string { return "fixed" }
+
+func (p fixedProvider) Lookup(ctx context.Context, cep string) (*getCep.ViaCEP, error) {
+	return p.addr, nil
+}
+
+func TestRecordingProvider(t *testing.T) {
+	store := newTestStore(t)
+	provider := store.Recording(fixedProvider{&getCep.ViaCEP{Cep: "01001-000", Localidade: "São Paulo"}})
+
+	ctx := WithClient(context.Background(), "192.168.0.10")
+	for range 3 {
+		if _, err := provider.Lookup(ctx, "01001000"); err != nil {
+			t.Fatalf("Lookup() erro inesperado: %v", err)
+		}
+	}
+	// A gravação é assíncrona: Close espera a fila esvaziar
+	if err := provider.Close(context.Background()); err != nil {
+		t.Fatalf("Close() erro inesperado: %v", err)
+	}
+
+	history, _ := store.History(context.Background(), "01001000", 10)
+	if len(history) != 3 || history[0].Client != "192.168.0.10" || history[0].Status != StatusFound {
+		t.Errorf("History() = %+v; expect 3 buscas do client 192.168.0.10", history)
+	}
+
+	// Depois do Close a busca continua funcionando, só não grava mais
+	if _, err := provider.Lookup(ctx, "01001000"); err != nil {
+		t.Fatalf("Lookup() depois do Close erro inesperado: %v", err)
+	}
+	if err := provider.Close(context.Background()); err != nil {
+		t.Errorf("Close() repetido erro inesperado: %v", err)
+	}
+}
+
+// failingProvider devolve sempre o mesmo erro
+type failingProvider struct{ err error }
+
+func (p failingProvider) Name() string { return "failing" }
+
+func (p failingProvider) Lookup(ctx context.Context, cep string) (*getCep.ViaCEP, error) {
+	return nil, p.err
+}
+
+func TestRecordingProviderFailures(t *testing.T) {
+	tests := []struct {
+		name   string
+		err    error
+		expect string // Status gravado; vazio = não grava
+	}{
+		{"não encontrado", fmt.Errorf("%w: viacep", getCep.ErrCEPNotFound), StatusNotFound},
+		{"upstream fora", getCep.ErrUpstreamUnavailable, StatusFailed},
+		{"timeout", context.DeadlineExceeded, StatusFailed},
+		{"cancelado", context.Canceled, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := newTestStore(t)
+			provider := store.Recording(failingProvider{tt.err})
+
+			ctx := WithClient(context.Background(), "ana")
+			if _, err := provider.Lookup(ctx, "01009-999"); !errors.Is(err, tt.err) {
+				t.Fatalf("Lookup() erro = %v; expect %v", err, tt.err)
+			}
+			provider.Close(context.Background())
+
+			history, _ := store.History(context.Background(), "01009999", 10)
+			if tt.expect == "" {
+				if len(history) != 0 {
+					t.Errorf("History() = %+v; expect vazio", history)
+				}
+				return
+			}
+			if len(history) != 1 || history[0].Status != tt.expect || history[0].Error != tt.err.Error() || history[0].Client != "ana" {
+				t.Errorf("History() = %+v; expect 1 busca de ana com status %s e erro %q", history, tt.expect, tt.err)
+			}
+		})
+	}
+}
@@ -0,0 +1,292 @@
+package main
+
+import (
+	"GoProject/1_moduleFoundation/5_cep-handler/getCep"
+	_ "embed"
+	"encoding/json"
+	"encoding/xml"
+	"flag"
+	"fmt"
+	"log"
+	"math/rand/v2"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+// Servidor falso com as mesmas URLs da ViaCEP, para desenvolver e testar sem internet.
+//
+// go run ./1_moduleFoundation/5_cep-handler/cmd/fakeviacep
+// go run ./1_moduleFoundation/5_cep-handler/cmd/fakeviacep -latency=300ms -jitter=200ms -error-rate=0.2
+// go run ./1_moduleFoundation/5_cep-handler/cmd/fakeviacep -fixtures=meus-ceps.json -fail=01001000
+//
+// Rotas (iguais às da ViaCEP):
+//
+//	GET /ws/{cep}/json/                       ==> endereço ou {"erro": true}
+//	GET /ws/{cep}/xml/
+//	GET /ws/{uf}/{cidade}/{logradouro}/json/  ==> busca por endereço (lista)
+//	GET /ws/{uf}/{cidade}/{logradouro}/xml/
+//
+// Para apontar o servidor de CEP e o buscaCep para cá:
+//
+//	VIACEP_BASE_URL=http://localhost:8081/ws/ go run ./1_moduleFoundation/4_buscaCep 01001000
+//	go run ./1_moduleFoundation/5_cep-handler -viacep-url=http://localhost:8081/ws/
+
+//go:embed fixtures/ceps.json
+var defaultFixtures []byte
+
+// maxSearchResults é o limite de itens da busca por endereço (igual à ViaCEP)
+const maxSearchResults = 50
+
+// options controla a latência e as falhas simuladas
+type options struct {
+	Latency     time.Duration   // Atraso fixo de toda resposta
+	Jitter      time.Duration   // Atraso extra aleatório entre 0 e Jitter
+	ErrorRate   float64         // Fração das requisições que falham com ErrorStatus (0 a 1)
+	ErrorStatus int             // Status das falhas simuladas (padrão 503)
+	Fail        map[string]bool // CEPs (8 dígitos) que sempre falham com ErrorStatus
+	ErroString  bool            // Responde {"erro": "true"} em vez de {"erro": true}
+}
+
+func main() {
+	addr := flag.String("addr", ":8081", "endereço do servidor http")
+	fixtures := flag.String("fixtures", "", "arquivo JSON com a lista de endereços (padrão: fixtures/ceps.json embutido)")
+	latency := flag.Duration("latency", 0, "atraso fixo de cada resposta")
+	jitter := flag.Duration("jitter", 0, "atraso extra aleatório (0 até o valor informado)")
+	errorRate := flag.Float64("error-rate", 0, "fração das requisições que falham (0 a 1)")
+	errorStatus := flag.Int("error-status", http.StatusServiceUnavailable, "status http das falhas simuladas")
+	fail := flag.String("fail", "", "CEPs separados por vírgula que sempre falham")
+	erroString := flag.Bool("erro-string", false, "responde {\"erro\": \"true\"} para CEP inexistente (formato antigo da ViaCEP)")
+	flag.Parse()
+
+	data := defaultFixtures
+	if *fixtures != "" {
+		var err error
+		if data, err = os.ReadFile(*fixtures); err != nil {
+			log.Fatalf("Erro ao ler fixtures: %v", err)
+		}
+	}
+	var addrs []getCep.ViaCEP
+	if err := json.Unmarshal(data, &addrs); err != nil {
+		log.Fatalf("Erro ao interpretar fixtures: %v", err)
+	}
+
+	opts := options{
+		Latency:     *latency,
+		Jitter:      *jitter,
+		ErrorRate:   *errorRate,
+		ErrorStatus: *errorStatus,
+		Fail:        make(map[string]bool),
+		ErroString:  *erroString,
+	}
+	for _, cep := range strings.Split(*fail, ",") {
+		if cep = onlyDigits(cep); cep != "" {
+			opts.Fail[cep] = true
+		}
+	}
+
+	server := newServer(addrs, opts)
+	log.Printf("Fake ViaCEP com %d CEPs ouvindo em %s", len(server.byCep), *addr)
+	log.Fatal(http.ListenAndServe(*addr, logRequests(server.routes())))
+}
+
+// fakeServer responde a partir das fixtures carregadas em memória
+type fakeServer struct {
+	opts  options
+	byCep map[string]getCep.ViaCEP // cep (8 dígitos) ==> endereço
+	all   []getCep.ViaCEP
+}
+
+func newServer(addrs []getCep.ViaCEP, opts options) *fakeServer {
+	if opts.ErrorStatus == 0 {
+		opts.ErrorStatus = http.StatusServiceUnavailable
+	}
+	s := &fakeServer{opts: opts, byCep: make(map[string]getCep.ViaCEP)}
+	for _, addr := range addrs {
+		if key := onlyDigits(addr.Cep); len(key) == 8 {
+			s.byCep[key] = addr
+			s.all = append(s.all, addr)
+		}
+	}
+	return s
+}
+
+// routes registra as rotas no formato da ViaCEP ({$} evita casar caminhos mais longos)
+func (s *fakeServer) routes() *http.ServeMux {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /ws/{cep}/{format}/{$}", s.cepHandler)
+	mux.HandleFunc("GET /ws/{uf}/{cidade}/{logradouro}/{format}/{$}", s.searchHandler)
+	return mux
+}
+
+func (s *fakeServer) cepHandler(w http.ResponseWriter, r *http.Request) {
+	cep := r.PathValue("cep")
+	if !s.simulate(w, r, cep) {
+		return
+	}
+
+	// A ViaCEP só aceita 8 dígitos, sem máscara
+	if len(cep) != 8 || onlyDigits(cep) != cep {
+		http.Error(w, "Bad Request", http.StatusBadRequest)
+		return
+	}
+
+	addr, ok := s.byCep[cep]
+	if !ok {
+		s.writeErro(w, r.PathValue("format"))
+		return
+	}
+	switch r.PathValue("format") {
+	case "json":
+		writeJSON(w, addr)
+	case "xml":
+		writeXML(w, xmlSingle{xmlAddress: xmlAddress(addr)})
+	default:
+		http.Error(w, "Bad Request", http.StatusBadRequest)
+	}
+}
+
+func (s *fakeServer) searchHandler(w http.ResponseWriter, r *http.Request) {
+	if !s.simulate(w, r, "") {
+		return
+	}
+
+	uf, cidade, logradouro := r.PathValue("uf"), r.PathValue("cidade"), r.PathValue("logradouro")
+	if len(uf) != 2 || len([]rune(cidade)) < 3 || len([]rune(logradouro)) < 3 {
+		http.Error(w, "Bad Request", http.StatusBadRequest)
+		return
+	}
+
+	results := []getCep.ViaCEP{}
+	for _, addr := range s.all {
+		if len(results) == maxSearchResults {
+			break
+		}
+		if strings.EqualFold(addr.Uf, uf) && strings.EqualFold(addr.Localidade, cidade) &&
+			strings.Contains(strings.ToLower(addr.Logradouro), strings.ToLower(logradouro)) {
+			results = append(results, addr)
+		}
+	}
+
+	switch r.PathValue("format") {
+	case "json":
+		writeJSON(w, results)
+	case "xml":
+		list := xmlList{}
+		for _, addr := range results {
+			list.Enderecos = append(list.Enderecos, xmlAddress(addr))
+		}
+		writeXML(w, list)
+	default:
+		http.Error(w, "Bad Request", http.StatusBadRequest)
+	}
+}
+
+// simulate aplica latência e falhas; devolve false se a resposta já foi escrita
+func (s *fakeServer) simulate(w http.ResponseWriter, r *http.Request, cep string) bool {
+	delay := s.opts.Latency
+	if s.opts.Jitter > 0 {
+		delay += rand.N(s.opts.Jitter)
+	}
+	if delay > 0 {
+		select {
+		case <-time.After(delay):
+		case <-r.Context().Done():
+			return false // Cliente desistiu
+		}
+	}
+
+	if s.opts.Fail[cep] || (s.opts.ErrorRate > 0 && rand.Float64() < s.opts.ErrorRate) {
+		http.Error(w, http.StatusText(s.opts.ErrorStatus), s.opts.ErrorStatus)
+		return false
+	}
+	return true
+}
+
+// writeErro responde como a ViaCEP para CEP com formato válido que não existe (status 200)
+func (s *fakeServer) writeErro(w http.ResponseWriter, format string) {
+	var erro any = true
+	if s.opts.ErroString {
+		erro = "true"
+	}
+	switch format {
+	case "json":
+		writeJSON(w, map[string]any{"erro": erro})
+	case "xml":
+		writeXML(w, xmlErro{Erro: "true"})
+	default:
+		http.Error(w, "Bad Request", http.StatusBadRequest)
+	}
+}
+
+// ############################## FORMATOS #####################################
+
+// xmlAddress tem os mesmos campos da getCep.ViaCEP (permite conversão direta) com as tags do XML da ViaCEP
+type xmlAddress struct {
+	Cep         string `xml:"cep"`
+	Logradouro  string `xml:"logradouro"`
+	Complemento string `xml:"complemento"`
+	Unidade     string `xml:"unidade"`
+	Bairro      string `xml:"bairro"`
+	Localidade  string `xml:"localidade"`
+	Uf          string `xml:"uf"`
+	Estado      string `xml:"estado"`
+	Regiao      string `xml:"regiao"`
+	Ibge        string `xml:"ibge"`
+	Gia         string `xml:"gia"`
+	Ddd         string `xml:"ddd"`
+	Siafi       string `xml:"siafi"`
+}
+
+// <xmlcep><cep>...</cep>...</xmlcep>
+type xmlSingle struct {
+	XMLName xml.Name `xml:"xmlcep"`
+	xmlAddress
+}
+
+// <xmlcep><enderecos><endereco>...</endereco></enderecos></xmlcep>
+type xmlList struct {
+	XMLName   xml.Name     `xml:"xmlcep"`
+	Enderecos []xmlAddress `xml:"enderecos>endereco"`
+}
+
+// <xmlcep><erro>true</erro></xmlcep>
+type xmlErro struct {
+	XMLName xml.Name `xml:"xmlcep"`
+	Erro    string   `xml:"erro"`
+}
+
+func writeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	encoder.Encode(v)
+}
+
+func writeXML(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/xml; charset=utf-8")
+	fmt.Fprint(w, xml.Header)
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	encoder.Encode(v)
+}
+
+// logRequests loga método, caminho e tempo de cada requisição
+func logRequests(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		next.ServeHTTP(w, r)
+		log.Printf("%s %s (%s)", r.Method, r.URL.Path, time.Since(start).Round(time.Millisecond))
+	})
+}
+
+// onlyDigits remove tudo que não é dígito ("01001-000" ==> "01001000")
+func onlyDigits(s string) string {
+	return strings.Map(func(r rune) rune {
+		if r >= '0' && r <= '9' {
+			return r
+		}
+		return -1
+	}, s)
+}
@@ -0,0 +1,118 @@
+package main
+
+import (
+	"GoProject/1_moduleFoundation/5_cep-handler/getCep"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// newTestViaCEP sobe o fake com as fixtures embutidas e devolve um provider apontando para ele
+func newTestViaCEP(t *testing.T, opts options) *getCep.ViaCEPProvider {
+	t.Helper()
+	var addrs []getCep.ViaCEP
+	if err := json.Unmarshal(defaultFixtures, &addrs); err != nil {
+		t.Fatalf("fixtures inválidas: %v", err)
+	}
+	server := httptest.NewServer(newServer(addrs, opts).routes())
+	t.Cleanup(server.Close)
+	return &getCep.ViaCEPProvider{BaseURL: server.URL + "/ws/", Retry: getCep.RetryPolicy{MaxAttempts: 1}}
+}
+
+func TestFakeLookup(t *testing.T) {
+	tests := []struct {
+		name   string
+		opts   options
+		cep    string
+		expect string // Logradouro esperado
+		err    error
+	}{
+		{"encontrado", options{}, "01001000", "Praça da Sé", nil},
+		{"erro true", options{}, "01001999", "", getCep.ErrCEPNotFound},
+		{"erro \"true\"", options{ErroString: true}, "01001999", "", getCep.ErrCEPNotFound},
+		{"falha injetada", options{Fail: map[string]bool{"01001000": true}}, "01001000", "", getCep.ErrUpstreamUnavailable},
+		{"error-rate 100%", options{ErrorRate: 1, ErrorStatus: http.StatusInternalServerError}, "01001000", "", getCep.ErrUpstreamUnavailable},
+		{"formato inválido", options{}, "0100100", "", getCep.ErrInvalidCEP},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			viaCEP := newTestViaCEP(t, tt.opts)
+			addr, err := viaCEP.Lookup(context.Background(), tt.cep)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("Lookup(%q) erro = %v; expect %v", tt.cep, err, tt.err)
+			}
+			if tt.err == nil && addr.Logradouro != tt.expect {
+				t.Errorf("Lookup(%q).Logradouro = %q; expect %q", tt.cep, addr.Logradouro, tt.expect)
+			}
+		})
+	}
+}
+
+func TestFakeLatency(t *testing.T) {
+	viaCEP := newTestViaCEP(t, options{Latency: time.Second})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if _, err := viaCEP.Lookup(ctx, "01001000"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Lookup() erro = %v; expect %v", err, context.DeadlineExceeded)
+	}
+}
+
+func TestFakeSearch(t *testing.T) {
+	viaCEP := newTestViaCEP(t, options{})
+
+	results, err := viaCEP.Search(context.Background(), "SP", "São Paulo", "paulista")
+	if err != nil {
+		t.Fatalf("Search() erro inesperado: %v", err)
+	}
+	if len(results) != 2 {
+		t.Fatalf("Search() = %d resultados; expect 2", len(results))
+	}
+
+	if _, err := viaCEP.Search(context.Background(), "SP", "São Paulo", "Pa"); !errors.Is(err, getCep.ErrInvalidSearch) {
+		t.Errorf("Search() logradouro curto: erro = %v; expect %v", err, getCep.ErrInvalidSearch)
+	}
+}
+
+func TestFakeXML(t *testing.T) {
+	var addrs []getCep.ViaCEP
+	json.Unmarshal(defaultFixtures, &addrs)
+	server := httptest.NewServer(newServer(addrs, options{}).routes())
+	defer server.Close()
+
+	tests := []struct {
+		path     string
+		contains []string
+	}{
+		{"/ws/01001000/xml/", []string{"<xmlcep>", "<cep>01001-000</cep>", "<logradouro>Praça da Sé</logradouro>"}},
+		{"/ws/01001999/xml/", []string{"<xmlcep>", "<erro>true</erro>"}},
+		{"/ws/SP/São Paulo/Paulista/xml/", []string{"<enderecos>", "<endereco>", "<cep>01310-100</cep>"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			resp, err := http.Get(server.URL + strings.ReplaceAll(tt.path, " ", "%20"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer resp.Body.Close()
+			body, _ := io.ReadAll(resp.Body)
+
+			if resp.StatusCode != http.StatusOK {
+				t.Fatalf("status = %d; expect 200", resp.StatusCode)
+			}
+			for _, want := range tt.contains {
+				if !strings.Contains(string(body), want) {
+					t.Errorf("resposta sem %q:\n%s", want, body)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,176 @@
+package main
+
+import (
+	"GoProject/1_moduleFoundation/5_cep-handler/getCep"
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Importa um dump de endereços (CSV ou NDJSON) para o índice offline do getCep.
+//
+// go run ./1_moduleFoundation/5_cep-handler/cmd/importcep -in=enderecos.csv -out=ceps.idx
+// go run ./1_moduleFoundation/5_cep-handler/cmd/importcep -in=enderecos.ndjson -out=ceps.idx
+//
+// CSV: primeira linha com os nomes das colunas iguais às tags json da ViaCEP
+// (cep,logradouro,bairro,localidade,uf,ibge,ddd,...). Colunas desconhecidas são ignoradas.
+// NDJSON: um objeto ViaCEP por linha.
+//
+// Depois suba o servidor com: go run ./1_moduleFoundation/5_cep-handler -offline-index=ceps.idx
+
+func main() {
+	in := flag.String("in", "", "arquivo CSV ou NDJSON com os endereços (\"-\" = entrada padrão)")
+	out := flag.String("out", "ceps.idx", "arquivo de índice gerado")
+	format := flag.String("format", "", "csv ou ndjson (padrão: pela extensão de -in)")
+	flag.Parse()
+
+	if *in == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+	if *format == "" {
+		*format = formatFromExt(*in)
+	}
+
+	var input io.Reader = os.Stdin
+	if *in != "-" {
+		file, err := os.Open(*in)
+		if err != nil {
+			log.Fatalf("Erro ao abrir %s: %v", *in, err)
+		}
+		defer file.Close()
+		input = file
+	}
+
+	addrs, skipped, err := readAddresses(input, *format)
+	if err != nil {
+		log.Fatalf("Erro ao ler endereços: %v", err)
+	}
+
+	written, err := writeIndexFile(*out, addrs)
+	if err != nil {
+		log.Fatalf("Erro ao gravar índice: %v", err)
+	}
+	fmt.Printf("%d CEPs gravados em %s (%d linhas ignoradas)\n", written, *out, skipped+len(addrs)-written)
+}
+
+// formatFromExt escolhe o formato pela extensão do arquivo
+func formatFromExt(path string) string {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".ndjson", ".jsonl", ".json":
+		return "ndjson"
+	default:
+		return "csv"
+	}
+}
+
+// readAddresses lê o dump; linhas sem CEP válido são contadas em skipped
+func readAddresses(r io.Reader, format string) (addrs []getCep.ViaCEP, skipped int, err error) {
+	switch format {
+	case "csv":
+		return readCSV(r)
+	case "ndjson":
+		return readNDJSON(r)
+	}
+	return nil, 0, fmt.Errorf("formato desconhecido: %q (use csv ou ndjson)", format)
+}
+
+func readCSV(r io.Reader) ([]getCep.ViaCEP, int, error) {
+	reader := csv.NewReader(bufio.NewReader(r))
+	reader.FieldsPerRecord = -1 // Tolera linhas com colunas faltando
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, 0, fmt.Errorf("cabeçalho: %w", err)
+	}
+	columns := make(map[string]int)
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))] = i
+	}
+	if _, ok := columns["cep"]; !ok {
+		return nil, 0, errors.New("cabeçalho sem a coluna cep")
+	}
+
+	var addrs []getCep.ViaCEP
+	skipped := 0
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, 0, err
+		}
+
+		get := func(name string) string {
+			if i, ok := columns[name]; ok && i < len(record) {
+				return strings.TrimSpace(record[i])
+			}
+			return ""
+		}
+		addr := getCep.ViaCEP{
+			Cep: get("cep"), Logradouro: get("logradouro"), Complemento: get("complemento"),
+			Unidade: get("unidade"), Bairro: get("bairro"), Localidade: get("localidade"),
+			Uf: get("uf"), Estado: get("estado"), Regiao: get("regiao"), Ibge: get("ibge"),
+			Gia: get("gia"), Ddd: get("ddd"), Siafi: get("siafi"),
+		}
+		if _, err := getCep.Normalize(addr.Cep); err != nil {
+			skipped++
+			continue
+		}
+		addrs = append(addrs, addr)
+	}
+	return addrs, skipped, nil
+}
+
+func readNDJSON(r io.Reader) ([]getCep.ViaCEP, int, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+
+	var addrs []getCep.ViaCEP
+	skipped := 0
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		var addr getCep.ViaCEP
+		if err := json.Unmarshal([]byte(line), &addr); err != nil {
+			skipped++
+			continue
+		}
+		if _, err := getCep.Normalize(addr.Cep); err != nil {
+			skipped++
+			continue
+		}
+		addrs = append(addrs, addr)
+	}
+	return addrs, skipped, scanner.Err()
+}
+
+// writeIndexFile grava o índice em um arquivo temporário e renomeia no final
+func writeIndexFile(path string, addrs []getCep.ViaCEP) (int, error) {
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return 0, err
+	}
+	defer os.Remove(tmp.Name()) // Sem efeito após o rename
+
+	written, err := getCep.WriteIndex(tmp, addrs)
+	if err != nil {
+		tmp.Close()
+		return 0, err
+	}
+	if err := tmp.Close(); err != nil {
+		return 0, err
+	}
+	return written, os.Rename(tmp.Name(), path)
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestReadAddresses(t *testing.T) {
+	tests := []struct {
+		name          string
+		format        string
+		input         string
+		expectCount   int
+		expectSkipped int
+	}{
+		{
+			name:   "csv",
+			format: "csv",
+			input: "cep,logradouro,localidade,uf,extra\n" +
+				"01001-000,Praça da Sé,São Paulo,SP,x\n" +
+				"123,inválido,,,\n" +
+				"20040020,Avenida Rio Branco,Rio de Janeiro\n",
+			expectCount:   2,
+			expectSkipped: 1,
+		},
+		{
+			name:   "ndjson",
+			format: "ndjson",
+			input: `{"cep":"01001-000","logradouro":"Praça da Sé"}` + "\n\n" +
+				`{"cep":"0100"}` + "\n" +
+				`não é json` + "\n" +
+				`{"cep":"20040020","uf":"RJ"}` + "\n",
+			expectCount:   2,
+			expectSkipped: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			addrs, skipped, err := readAddresses(strings.NewReader(tt.input), tt.format)
+			if err != nil {
+				t.Fatalf("readAddresses() erro inesperado: %v", err)
+			}
+			if len(addrs) != tt.expectCount || skipped != tt.expectSkipped {
+				t.Errorf("readAddresses() = %d endereços, %d ignorados; expect %d, %d", len(addrs), skipped, tt.expectCount, tt.expectSkipped)
+			}
+		})
+	}
+}
+
+func TestReadAddressesCSVColumns(t *testing.T) {
+	addrs, _, err := readAddresses(strings.NewReader("UF,Localidade,CEP\nSP,São Paulo,01001000\n"), "csv")
+	if err != nil || len(addrs) != 1 {
+		t.Fatalf("readAddresses() = %v, %v", addrs, err)
+	}
+	if addrs[0].Uf != "SP" || addrs[0].Localidade != "São Paulo" {
+		t.Errorf("readAddresses() = %+v; expect colunas mapeadas pelo cabeçalho", addrs[0])
+	}
+
+	if _, _, err := readAddresses(strings.NewReader("logradouro\nRua A\n"), "csv"); err == nil {
+		t.Error("readAddresses() sem coluna cep: expect erro")
+	}
+	if _, _, err := readAddresses(strings.NewReader(""), "xml"); err == nil {
+		t.Error("readAddresses() formato desconhecido: expect erro")
+	}
+}
@@ -0,0 +1,224 @@
+package main
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"GoProject/1_moduleFoundation/2_HTTPClient/httpClient"
+	"GoProject/1_moduleFoundation/5_cep-handler/municipios"
+)
+
+// Gera o municipios.csv embutido no pacote municipios com todos os municípios do IBGE.
+//
+// cd 1_moduleFoundation/5_cep-handler/municipios && go generate
+// go run ./1_moduleFoundation/5_cep-handler/cmd/importmunicipios -out municipios.csv -ddd ddds.csv
+// go run ./1_moduleFoundation/5_cep-handler/cmd/importmunicipios -in municipios.json   (JSON já baixado)
+//
+// A API do IBGE não informa DDD. Os DDDs vêm do -ddd (por padrão a base aberta
+// municipios-brasileiros, com uma linha por código IBGE) e, para o que faltar,
+// do próprio -out anterior. Qualquer CSV com as colunas codigo (ou codigo_ibge)
+// e ddd serve, ex.: exportado da tabela de áreas de numeração da Anatel.
+// A geração falha se algum município ficar sem DDD.
+
+// ibgeURL lista todos os municípios: [{"id":3550308,"nome":"São Paulo",...}, ...]
+const ibgeURL = "https://servicodados.ibge.gov.br/api/v1/localidades/municipios"
+
+// dddURL tem codigo_ibge e ddd de todos os municípios
+const dddURL = "https://raw.githubusercontent.com/kelvins/municipios-brasileiros/main/csv/municipios.csv"
+
+// minMunicipios protege contra gravar uma resposta truncada da API por cima da tabela
+const minMunicipios = 5500
+
+func main() {
+	url := flag.String("url", ibgeURL, "API de localidades do IBGE")
+	in := flag.String("in", "", "JSON da API já baixado (no lugar de -url)")
+	out := flag.String("out", "municipios.csv", "CSV gerado (codigo,nome,uf,ddd)")
+	dddSource := flag.String("ddd", dddURL, "CSV (arquivo ou URL) com as colunas codigo/codigo_ibge e ddd")
+	flag.Parse()
+
+	var input io.Reader
+	if *in != "" {
+		file, err := os.Open(*in)
+		if err != nil {
+			log.Fatalf("Erro ao abrir %s: %v", *in, err)
+		}
+		defer file.Close()
+		input = file
+	} else {
+		body, err := fetch(*url)
+		if err != nil {
+			log.Fatalf("Erro ao consultar o IBGE: %v", err)
+		}
+		defer body.Close()
+		input = body
+	}
+
+	rows, err := readIBGE(input)
+	if err != nil {
+		log.Fatalf("Erro ao ler municípios: %v", err)
+	}
+	if len(rows) < minMunicipios && *in == "" {
+		log.Fatalf("O IBGE devolveu só %d municípios; esperado ao menos %d", len(rows), minMunicipios)
+	}
+
+	// Mais baixo para mais alto: o -out atual, depois o -ddd
+	ddds := map[string]string{}
+	for _, source := range []string{*out, *dddSource} {
+		if source == "" {
+			continue
+		}
+		if err := readDDDs(source, ddds); err != nil && !(source == *out && os.IsNotExist(err)) {
+			log.Fatalf("Erro ao ler DDDs de %s: %v", source, err)
+		}
+	}
+	if missing := missingDDDs(rows, ddds); len(missing) > 0 && *in == "" {
+		log.Fatalf("%d municípios sem DDD (ex.: %s); complete o -ddd", len(missing), strings.Join(missing[:min(len(missing), 5)], ", "))
+	}
+
+	missing, err := writeCSV(*out, rows, ddds)
+	if err != nil {
+		log.Fatalf("Erro ao gravar %s: %v", *out, err)
+	}
+	fmt.Printf("%d municípios gravados em %s (%d sem DDD)\n", len(rows), *out, missing)
+}
+
+// fetch baixa a lista do IBGE (ou a tabela de DDDs) com o client do projeto (timeout e retry)
+func fetch(url string) (io.ReadCloser, error) {
+	client := httpClient.New(httpClient.Options{
+		Timeout:   time.Minute,
+		UserAgent: "GoProject-importmunicipios/1.0",
+		Retry:     httpClient.RetryPolicy{MaxAttempts: 3, BaseDelay: time.Second, MaxDelay: 5 * time.Second},
+	})
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, fmt.Errorf("GET %s: %s", url, resp.Status)
+	}
+	return resp.Body, nil
+}
+
+// row é um município pronto para o CSV
+type row struct {
+	codigo, nome, uf string
+}
+
+// readIBGE lê a resposta da API; a UF sai do código (os 2 primeiros dígitos),
+// que não depende da microrregião, nula para alguns municípios recentes
+func readIBGE(r io.Reader) ([]row, error) {
+	var list []struct {
+		ID   int    `json:"id"`
+		Nome string `json:"nome"`
+	}
+	if err := json.NewDecoder(r).Decode(&list); err != nil {
+		return nil, err
+	}
+
+	rows := make([]row, 0, len(list))
+	for _, m := range list {
+		codigo := strconv.Itoa(m.ID)
+		uf, ok := municipios.UFOfCode(codigo)
+		if !ok || strings.TrimSpace(m.Nome) == "" {
+			return nil, fmt.Errorf("município inválido: %d %q", m.ID, m.Nome)
+		}
+		rows = append(rows, row{codigo: codigo, nome: strings.TrimSpace(m.Nome), uf: uf})
+	}
+	slices.SortFunc(rows, func(a, b row) int { return strings.Compare(a.codigo, b.codigo) })
+	return rows, nil
+}
+
+// readDDDs acrescenta em ddds as colunas codigo (ou codigo_ibge) e ddd do CSV,
+// lido de um arquivo ou de uma URL http(s); outras colunas são ignoradas
+func readDDDs(source string, ddds map[string]string) error {
+	var file io.ReadCloser
+	var err error
+	if strings.HasPrefix(source, "http://") || strings.HasPrefix(source, "https://") {
+		file, err = fetch(source)
+	} else {
+		file, err = os.Open(source)
+	}
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	reader := csv.NewReader(file)
+	reader.FieldsPerRecord = -1
+	records, err := reader.ReadAll()
+	if err != nil {
+		return err
+	}
+	if len(records) == 0 {
+		return nil
+	}
+	codigo, ddd := slices.Index(records[0], "codigo"), slices.Index(records[0], "ddd")
+	if codigo < 0 {
+		codigo = slices.Index(records[0], "codigo_ibge")
+	}
+	if codigo < 0 || ddd < 0 {
+		return fmt.Errorf("cabeçalho sem as colunas codigo (ou codigo_ibge) e ddd: %v", records[0])
+	}
+	for _, record := range records[1:] {
+		if max(codigo, ddd) < len(record) && record[ddd] != "" {
+			ddds[record[codigo]] = record[ddd]
+		}
+	}
+	return nil
+}
+
+// missingDDDs lista os códigos dos municípios sem DDD em ddds
+func missingDDDs(rows []row, ddds map[string]string) []string {
+	var missing []string
+	for _, r := range rows {
+		if ddds[r.codigo] == "" {
+			missing = append(missing, r.codigo)
+		}
+	}
+	return missing
+}
+
+// writeCSV grava por arquivo temporário + rename; devolve quantos ficaram sem DDD
+func writeCSV(path string, rows []row, ddds map[string]string) (missing int, err error) {
+	tmp := path + ".tmp"
+	file, err := os.Create(tmp)
+	if err != nil {
+		return 0, err
+	}
+	defer os.Remove(tmp)
+
+	w := csv.NewWriter(file)
+	w.Write([]string{"codigo", "nome", "uf", "ddd"})
+	for _, r := range rows {
+		ddd := ddds[r.codigo]
+		if ddd == "" {
+			missing++
+		}
+		w.Write([]string{r.codigo, r.nome, r.uf, ddd})
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		file.Close()
+		return 0, err
+	}
+	if err := file.Close(); err != nil {
+		return 0, err
+	}
+	return missing, os.Rename(tmp, path)
+}
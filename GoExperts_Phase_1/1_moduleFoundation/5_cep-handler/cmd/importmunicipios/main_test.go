@@ -0,0 +1,60 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestImport(t *testing.T) {
+	file, err := os.Open("testdata/municipios.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	rows, err := readIBGE(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dir := t.TempDir()
+	out := filepath.Join(dir, "municipios.csv")
+	anatel := filepath.Join(dir, "ddds.csv")
+	// O -out anterior tem o DDD de São Paulo; o -ddd tem o de Brasília e corrige o de São Paulo
+	os.WriteFile(out, []byte("codigo,nome,uf,ddd\n3550308,São Paulo,SP,10\n1100015,Alta Floresta D'Oeste,RO,69\n"), 0o644)
+	// Mesmo formato da base padrão do -ddd: codigo_ibge entre outras colunas
+	os.WriteFile(anatel, []byte("codigo_ibge,nome,ddd\n3550308,São Paulo,11\n5300108,Brasília,61\n"), 0o644)
+
+	ddds := map[string]string{}
+	for _, path := range []string{out, anatel} {
+		if err := readDDDs(path, ddds); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if got := missingDDDs(rows, ddds); len(got) != 1 || got[0] != "5101837" {
+		t.Errorf("missingDDDs() = %v; expect [5101837]", got)
+	}
+	missing, err := writeCSV(out, rows, ddds)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, _ := os.ReadFile(out)
+	expect := "codigo,nome,uf,ddd\n" +
+		"1100015,Alta Floresta D'Oeste,RO,69\n" +
+		"3550308,São Paulo,SP,11\n" +
+		"5101837,Boa Esperança do Norte,MT,\n" +
+		"5300108,Brasília,DF,61\n"
+	if string(got) != expect || missing != 1 {
+		t.Errorf("CSV (%d sem DDD) =\n%s\nexpect (1 sem DDD)\n%s", missing, got, expect)
+	}
+}
+
+func TestReadIBGEInvalid(t *testing.T) {
+	for _, input := range []string{`[{"id": 9900001, "nome": "X"}]`, `[{"id": 3550308, "nome": " "}]`, `{`} {
+		if _, err := readIBGE(strings.NewReader(input)); err == nil {
+			t.Errorf("readIBGE(%s) sem erro", input)
+		}
+	}
+}
@@ -0,0 +1,66 @@
+package getCep
+
+import (
+	"context"
+	"sync"
+)
+
+// BatchResult é o resultado de um CEP dentro de um lote
+type BatchResult struct {
+	Cep     string  // CEP como foi recebido
+	Address *ViaCEP // nil quando Err != nil
+	Err     error
+}
+
+// LookupBatch resolve os CEPs com GetCep usando um pool de no máximo
+// `concurrency` workers. O resultado mantém a ordem da entrada e cada item
+// traz o seu próprio erro. Se o ctx for cancelado, os CEPs ainda não
+// processados recebem ctx.Err() sem chamar o upstream.
+func LookupBatch(ctx context.Context, ceps []string, concurrency int) []BatchResult {
+	results := make([]BatchResult, len(ceps))
+	// Cada worker escreve apenas em results[i]: sem mutex
+	LookupEach(ctx, ceps, concurrency, func(i int, res BatchResult) {
+		results[i] = res
+	})
+	return results
+}
+
+// LookupEach funciona como LookupBatch, mas entrega cada resultado para fn
+// assim que ele fica pronto (fora de ordem; i é a posição em ceps).
+// fn é chamada pelos workers em paralelo e deve ser segura para isso.
+// Retorna quando todos os CEPs foram entregues.
+func LookupEach(ctx context.Context, ceps []string, concurrency int, fn func(i int, res BatchResult)) {
+	if len(ceps) == 0 {
+		return
+	}
+	if concurrency <= 0 {
+		concurrency = 1
+	}
+	if concurrency > len(ceps) {
+		concurrency = len(ceps)
+	}
+
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for range concurrency {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				res := BatchResult{Cep: ceps[i]}
+				if err := ctx.Err(); err != nil {
+					res.Err = err
+				} else {
+					res.Address, res.Err = GetCep(ctx, ceps[i])
+				}
+				fn(i, res)
+			}
+		}()
+	}
+
+	for i := range ceps {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+}
@@ -0,0 +1,103 @@
+package getCep
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// slowProvider mede quantas buscas rodam ao mesmo tempo
+type slowProvider struct {
+	delay            time.Duration
+	running, maxSeen atomic.Int32
+	calls            atomic.Int32
+}
+
+func (p *slowProvider) Name() string { return "slow" }
+
+func (p *slowProvider) Lookup(ctx context.Context, cep string) (*ViaCEP, error) {
+	p.calls.Add(1)
+	now := p.running.Add(1)
+	defer p.running.Add(-1)
+	for {
+		seen := p.maxSeen.Load()
+		if now <= seen || p.maxSeen.CompareAndSwap(seen, now) {
+			break
+		}
+	}
+
+	select {
+	case <-time.After(p.delay):
+		return &ViaCEP{Cep: cep[:5] + "-" + cep[5:]}, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// useDefaultProvider troca o DefaultProvider durante o teste
+func useDefaultProvider(t *testing.T, p Provider) {
+	t.Helper()
+	previous := DefaultProvider
+	DefaultProvider = p
+	t.Cleanup(func() { DefaultProvider = previous })
+}
+
+func TestLookupBatch(t *testing.T) {
+	upstream := &slowProvider{delay: 10 * time.Millisecond}
+	useDefaultProvider(t, upstream)
+
+	var ceps []string
+	for i := range 20 {
+		ceps = append(ceps, fmt.Sprintf("01%03d000", i+1))
+	}
+	ceps = append(ceps, "abc") // Inválido: erro só neste item
+
+	results := LookupBatch(context.Background(), ceps, 4)
+
+	if len(results) != len(ceps) {
+		t.Fatalf("len(results) = %d; expect %d", len(results), len(ceps))
+	}
+	for i, res := range results[:20] {
+		expect := ceps[i][:5] + "-" + ceps[i][5:]
+		if res.Err != nil || res.Cep != ceps[i] || res.Address.Cep != expect {
+			t.Errorf("results[%d] = %+v; expect %s", i, res, expect)
+		}
+	}
+	if last := results[20]; !errors.Is(last.Err, ErrInvalidCEP) {
+		t.Errorf("results[20].Err = %v; expect %v", last.Err, ErrInvalidCEP)
+	}
+	if got := upstream.maxSeen.Load(); got > 4 {
+		t.Errorf("buscas simultâneas = %d; expect no máximo 4", got)
+	}
+}
+
+func TestLookupBatchCanceled(t *testing.T) {
+	upstream := &slowProvider{delay: time.Second}
+	useDefaultProvider(t, upstream)
+
+	ceps := make([]string, 50)
+	for i := range ceps {
+		ceps[i] = "01001000"
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	results := LookupBatch(ctx, ceps, 5)
+
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("LookupBatch demorou %v após o cancelamento", elapsed)
+	}
+	if got := upstream.calls.Load(); got != 5 {
+		t.Errorf("chamadas ao upstream = %d; expect 5 (o restante não deve ser iniciado)", got)
+	}
+	for i, res := range results {
+		if !errors.Is(res.Err, context.DeadlineExceeded) {
+			t.Fatalf("results[%d].Err = %v; expect %v", i, res.Err, context.DeadlineExceeded)
+		}
+	}
+}
@@ -0,0 +1,247 @@
+package getCep
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+)
+
+// ErrCircuitOpen é devolvido enquanto o circuito está aberto (upstream não é chamado)
+var ErrCircuitOpen = errors.New("circuito aberto: serviço de cep temporariamente desativado")
+
+// CircuitOpenError informa em quanto tempo o circuito tenta fechar de novo.
+// errors.Is(err, ErrCircuitOpen) funciona normalmente.
+type CircuitOpenError struct {
+	RetryAfter time.Duration
+}
+
+func (e *CircuitOpenError) Error() string {
+	return fmt.Sprintf("%v (nova tentativa em %s)", ErrCircuitOpen, e.RetryAfter.Round(time.Second))
+}
+
+func (e *CircuitOpenError) Unwrap() error { return ErrCircuitOpen }
+
+// BreakerState é o estado do circuit breaker
+type BreakerState int
+
+const (
+	StateClosed   BreakerState = iota // Normal: chamadas passam
+	StateOpen                         // Falhando: chamadas são recusadas na hora
+	StateHalfOpen                     // Testando: algumas chamadas passam para ver se o upstream voltou
+)
+
+func (s BreakerState) String() string {
+	switch s {
+	case StateClosed:
+		return "closed"
+	case StateOpen:
+		return "open"
+	case StateHalfOpen:
+		return "half-open"
+	}
+	return "unknown"
+}
+
+func (s BreakerState) MarshalText() ([]byte, error) { return []byte(s.String()), nil }
+
+// BreakerOptions configura o Breaker. Campos zerados usam os valores padrão.
+type BreakerOptions struct {
+	FailureThreshold int           // Falhas seguidas para abrir o circuito (padrão 5)
+	CoolDown         time.Duration // Tempo aberto antes de testar de novo (padrão 30s)
+	HalfOpenMaxCalls int           // Chamadas de teste simultâneas no half-open (padrão 1)
+
+	// OnStateChange é chamado a cada transição (além do log padrão).
+	// Roda com o breaker travado: não deve chamar métodos do Breaker.
+	OnStateChange func(from, to BreakerState)
+}
+
+// BreakerTransition registra uma mudança de estado
+type BreakerTransition struct {
+	From BreakerState `json:"from"`
+	To   BreakerState `json:"to"`
+	At   time.Time    `json:"at"`
+}
+
+// BreakerStatus é a foto do breaker exposta no endpoint de status
+type BreakerStatus struct {
+	Name        string              `json:"name"`
+	State       BreakerState        `json:"state"`
+	Failures    int                 `json:"consecutive_failures"`
+	Threshold   int                 `json:"failure_threshold"`
+	CoolDown    string              `json:"cool_down"`
+	RetryAfter  string              `json:"retry_after,omitempty"`
+	Transitions []BreakerTransition `json:"transitions"`
+}
+
+// maxTransitions limita o histórico de transições guardado em memória
+const maxTransitions = 20
+
+// Breaker é um Provider que protege o upstream com um circuit breaker:
+//   - closed: N falhas seguidas abrem o circuito
+//   - open: recusa as chamadas até o fim do cool-down, depois vai para half-open
+//   - half-open: deixa passar chamadas de teste; sucesso fecha, falha abre de novo
+//
+// Só falhas do upstream contam (ErrUpstreamUnavailable e timeout do próprio
+// upstream); CEP inválido ou não encontrado são respostas válidas. Chamadas em
+// que o ctx de quem chamou acabou (deadline curto, cancelamento) não contam.
+type Breaker struct {
+	upstream Provider
+	opts     BreakerOptions
+	now      func() time.Time // Substituído nos testes
+
+	mu          sync.Mutex
+	state       BreakerState
+	failures    int
+	openedAt    time.Time
+	trials      int // Chamadas de teste em andamento no half-open
+	transitions []BreakerTransition
+}
+
+// NewBreaker cria um Breaker na frente do upstream
+func NewBreaker(upstream Provider, opts BreakerOptions) *Breaker {
+	if opts.FailureThreshold <= 0 {
+		opts.FailureThreshold = 5
+	}
+	if opts.CoolDown <= 0 {
+		opts.CoolDown = 30 * time.Second
+	}
+	if opts.HalfOpenMaxCalls <= 0 {
+		opts.HalfOpenMaxCalls = 1
+	}
+	return &Breaker{upstream: upstream, opts: opts, now: time.Now}
+}
+
+func (b *Breaker) Name() string { return "breaker(" + b.upstream.Name() + ")" }
+
+func (b *Breaker) Lookup(ctx context.Context, cep string) (*ViaCEP, error) {
+	trial, err := b.allow()
+	if err != nil {
+		return nil, err
+	}
+
+	addr, err := b.upstream.Lookup(ctx, cep)
+	b.record(ctx, trial, err)
+	return addr, err
+}
+
+// State devolve o estado atual (já considerando o fim do cool-down)
+func (b *Breaker) State() BreakerState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.advance()
+	return b.state
+}
+
+// Status devolve estado, contadores e as últimas transições
+func (b *Breaker) Status() BreakerStatus {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.advance()
+
+	status := BreakerStatus{
+		Name:        b.upstream.Name(),
+		State:       b.state,
+		Failures:    b.failures,
+		Threshold:   b.opts.FailureThreshold,
+		CoolDown:    b.opts.CoolDown.String(),
+		Transitions: append([]BreakerTransition{}, b.transitions...),
+	}
+	if b.state == StateOpen {
+		status.RetryAfter = b.retryAfter().Round(time.Second).String()
+	}
+	return status
+}
+
+// allow decide se a chamada pode passar; trial = chamada de teste do half-open
+func (b *Breaker) allow() (trial bool, err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.advance()
+
+	switch b.state {
+	case StateOpen:
+		return false, &CircuitOpenError{RetryAfter: b.retryAfter()}
+	case StateHalfOpen:
+		if b.trials >= b.opts.HalfOpenMaxCalls {
+			return false, &CircuitOpenError{RetryAfter: time.Second}
+		}
+		b.trials++
+		return true, nil
+	}
+	return false, nil
+}
+
+// record contabiliza o resultado da chamada e muda de estado se preciso
+func (b *Breaker) record(ctx context.Context, trial bool, err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if trial {
+		b.trials--
+	}
+
+	// Cancelamento ou deadline de quem chamou não diz nada sobre a saúde do
+	// upstream: um cliente com deadline curto não pode abrir o circuito de todos.
+	// DeadlineExceeded com o ctx ainda vivo é timeout do próprio upstream e conta.
+	if ctx.Err() != nil || errors.Is(err, context.Canceled) {
+		return
+	}
+
+	failed := errors.Is(err, ErrUpstreamUnavailable) || errors.Is(err, context.DeadlineExceeded)
+	if !failed {
+		b.failures = 0
+		if b.state == StateHalfOpen {
+			b.transition(StateClosed)
+		}
+		return
+	}
+
+	b.failures++
+	switch {
+	case b.state == StateHalfOpen:
+		b.transition(StateOpen)
+	case b.state == StateClosed && b.failures >= b.opts.FailureThreshold:
+		b.transition(StateOpen)
+	}
+}
+
+// advance passa de open para half-open quando o cool-down acaba (chamar com mu travado)
+func (b *Breaker) advance() {
+	if b.state == StateOpen && b.retryAfter() <= 0 {
+		b.transition(StateHalfOpen)
+	}
+}
+
+// retryAfter é quanto falta para o fim do cool-down (chamar com mu travado)
+func (b *Breaker) retryAfter() time.Duration {
+	return b.openedAt.Add(b.opts.CoolDown).Sub(b.now())
+}
+
+// transition muda o estado, registra e loga (chamar com mu travado)
+func (b *Breaker) transition(to BreakerState) {
+	from := b.state
+	if from == to {
+		return
+	}
+	b.state = to
+	now := b.now()
+	if to == StateOpen {
+		b.openedAt = now
+	}
+	if to == StateClosed {
+		b.failures = 0
+	}
+
+	b.transitions = append(b.transitions, BreakerTransition{From: from, To: to, At: now})
+	if len(b.transitions) > maxTransitions {
+		b.transitions = b.transitions[len(b.transitions)-maxTransitions:]
+	}
+
+	log.Printf("Circuit breaker %s: %s -> %s", b.upstream.Name(), from, to)
+	if b.opts.OnStateChange != nil {
+		b.opts.OnStateChange(from, to)
+	}
+}
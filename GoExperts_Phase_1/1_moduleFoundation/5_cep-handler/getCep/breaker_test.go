@@ -0,0 +1,215 @@
+package getCep
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// scriptedProvider devolve o erro configurado em err (nil = sucesso)
+type scriptedProvider struct {
+	err   error
+	calls int
+}
+
+func (p *scriptedProvider) Name() string { return "scripted" }
+
+func (p *scriptedProvider) Lookup(ctx context.Context, cep string) (*ViaCEP, error) {
+	p.calls++
+	if p.err != nil {
+		return nil, p.err
+	}
+	return &ViaCEP{Cep: cep}, nil
+}
+
+func newTestBreaker(upstream Provider, opts BreakerOptions) (*Breaker, *fakeClock) {
+	clock := &fakeClock{t: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)}
+	b := NewBreaker(upstream, opts)
+	b.now = clock.Now
+	return b, clock
+}
+
+func TestBreakerOpensAfterThreshold(t *testing.T) {
+	upstream := &scriptedProvider{err: ErrUpstreamUnavailable}
+	var transitions []string
+	b, _ := newTestBreaker(upstream, BreakerOptions{
+		FailureThreshold: 3,
+		CoolDown:         time.Minute,
+		OnStateChange:    func(from, to BreakerState) { transitions = append(transitions, from.String()+">"+to.String()) },
+	})
+	ctx := context.Background()
+
+	for range 3 {
+		b.Lookup(ctx, "01001000")
+	}
+	if b.State() != StateOpen {
+		t.Fatalf("State() = %s; expect open", b.State())
+	}
+
+	// Aberto: falha rápido, sem chamar o upstream
+	_, err := b.Lookup(ctx, "01001000")
+	var open *CircuitOpenError
+	if !errors.As(err, &open) || !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("Lookup() erro = %v; expect %v", err, ErrCircuitOpen)
+	}
+	if open.RetryAfter != time.Minute {
+		t.Errorf("RetryAfter = %v; expect 1m", open.RetryAfter)
+	}
+	if upstream.calls != 3 {
+		t.Errorf("chamadas ao upstream = %d; expect 3", upstream.calls)
+	}
+	if len(transitions) != 1 || transitions[0] != "closed>open" {
+		t.Errorf("transições = %v; expect [closed>open]", transitions)
+	}
+}
+
+func TestBreakerIgnoresNonUpstreamErrors(t *testing.T) {
+	tests := []error{ErrCEPNotFound, ErrInvalidCEP, context.Canceled}
+
+	for _, e := range tests {
+		t.Run(e.Error(), func(t *testing.T) {
+			b, _ := newTestBreaker(&scriptedProvider{err: e}, BreakerOptions{FailureThreshold: 2})
+			for range 5 {
+				b.Lookup(context.Background(), "01001000")
+			}
+			if b.State() != StateClosed {
+				t.Errorf("State() = %s; expect closed (%v não é falha do upstream)", b.State(), e)
+			}
+		})
+	}
+}
+
+// waitingProvider só responde quando o ctx acaba, como um upstream lento
+type waitingProvider struct{}
+
+func (waitingProvider) Name() string { return "waiting" }
+
+func (waitingProvider) Lookup(ctx context.Context, cep string) (*ViaCEP, error) {
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+func TestBreakerIgnoresCallerDeadline(t *testing.T) {
+	b, _ := newTestBreaker(waitingProvider{}, BreakerOptions{FailureThreshold: 2})
+
+	for range 5 {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond)
+		if _, err := b.Lookup(ctx, "01001000"); !errors.Is(err, context.DeadlineExceeded) {
+			t.Fatalf("Lookup() erro = %v; expect %v", err, context.DeadlineExceeded)
+		}
+		cancel()
+	}
+	if b.State() != StateClosed {
+		t.Errorf("State() = %s; expect closed (o deadline era do cliente, não do upstream)", b.State())
+	}
+
+	// Timeout do próprio upstream, com o ctx de quem chamou vivo, conta como falha
+	b.upstream = &scriptedProvider{err: context.DeadlineExceeded}
+	for range 2 {
+		b.Lookup(context.Background(), "01001000")
+	}
+	if b.State() != StateOpen {
+		t.Errorf("State() = %s; expect open após timeouts do upstream", b.State())
+	}
+}
+
+func TestBreakerSuccessResetsFailures(t *testing.T) {
+	upstream := &scriptedProvider{err: ErrUpstreamUnavailable}
+	b, _ := newTestBreaker(upstream, BreakerOptions{FailureThreshold: 3})
+	ctx := context.Background()
+
+	b.Lookup(ctx, "01001000")
+	b.Lookup(ctx, "01001000")
+	upstream.err = nil
+	b.Lookup(ctx, "01001000")
+	upstream.err = ErrUpstreamUnavailable
+	b.Lookup(ctx, "01001000")
+	b.Lookup(ctx, "01001000")
+
+	if b.State() != StateClosed {
+		t.Errorf("State() = %s; expect closed (as falhas não foram seguidas)", b.State())
+	}
+}
+
+func TestBreakerHalfOpen(t *testing.T) {
+	upstream := &scriptedProvider{err: ErrUpstreamUnavailable}
+	b, clock := newTestBreaker(upstream, BreakerOptions{FailureThreshold: 1, CoolDown: 30 * time.Second})
+	ctx := context.Background()
+
+	b.Lookup(ctx, "01001000")
+	clock.Advance(30 * time.Second)
+	if b.State() != StateHalfOpen {
+		t.Fatalf("State() = %s; expect half-open após o cool-down", b.State())
+	}
+
+	// Teste falhou: volta a abrir com novo cool-down
+	b.Lookup(ctx, "01001000")
+	if b.State() != StateOpen {
+		t.Fatalf("State() = %s; expect open após falha no half-open", b.State())
+	}
+
+	// Teste passou: fecha
+	clock.Advance(30 * time.Second)
+	upstream.err = nil
+	if _, err := b.Lookup(ctx, "01001000"); err != nil {
+		t.Fatalf("Lookup() erro inesperado: %v", err)
+	}
+	if b.State() != StateClosed {
+		t.Fatalf("State() = %s; expect closed após sucesso no half-open", b.State())
+	}
+
+	status := b.Status()
+	expect := []string{"closed>open", "open>half-open", "half-open>open", "open>half-open", "half-open>closed"}
+	if len(status.Transitions) != len(expect) {
+		t.Fatalf("Transitions = %+v; expect %v", status.Transitions, expect)
+	}
+	for i, tr := range status.Transitions {
+		if got := tr.From.String() + ">" + tr.To.String(); got != expect[i] {
+			t.Errorf("Transitions[%d] = %s; expect %s", i, got, expect[i])
+		}
+	}
+}
+
+// blockingProvider segura a chamada até release ser fechado
+type blockingProvider struct {
+	started chan struct{}
+	release chan struct{}
+}
+
+func (p *blockingProvider) Name() string { return "blocking" }
+
+func (p *blockingProvider) Lookup(ctx context.Context, cep string) (*ViaCEP, error) {
+	p.started <- struct{}{}
+	<-p.release
+	return &ViaCEP{Cep: cep}, nil
+}
+
+func TestBreakerHalfOpenLimitsTrials(t *testing.T) {
+	b, clock := newTestBreaker(&scriptedProvider{err: ErrUpstreamUnavailable}, BreakerOptions{FailureThreshold: 1, CoolDown: time.Second})
+	b.Lookup(context.Background(), "01001000")
+	clock.Advance(time.Second)
+
+	slow := &blockingProvider{started: make(chan struct{}), release: make(chan struct{})}
+	b.upstream = slow
+
+	done := make(chan error)
+	go func() {
+		_, err := b.Lookup(context.Background(), "01001000")
+		done <- err
+	}()
+	<-slow.started
+
+	// Enquanto a chamada de teste não termina, as demais são recusadas
+	if _, err := b.Lookup(context.Background(), "01001000"); !errors.Is(err, ErrCircuitOpen) {
+		t.Errorf("Lookup() erro = %v; expect %v durante o teste do half-open", err, ErrCircuitOpen)
+	}
+
+	close(slow.release)
+	if err := <-done; err != nil {
+		t.Fatalf("chamada de teste: erro inesperado %v", err)
+	}
+	if b.State() != StateClosed {
+		t.Errorf("State() = %s; expect closed", b.State())
+	}
+}
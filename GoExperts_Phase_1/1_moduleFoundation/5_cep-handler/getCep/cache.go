@@ -0,0 +1,258 @@
+package getCep
+
+import (
+	"container/list"
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// CacheOptions configura o Cache. Campos zerados usam os valores padrão.
+type CacheOptions struct {
+	MaxEntries  int           // Limite de CEPs em memória (padrão 10.000); o menos usado sai primeiro (LRU)
+	TTL         time.Duration // Validade de um endereço encontrado (padrão 24h)
+	NegativeTTL time.Duration // Validade de um "CEP não encontrado" (padrão 10min)
+}
+
+// CacheStats expõe os contadores do cache
+type CacheStats struct {
+	Hits      uint64 `json:"hits"`
+	Misses    uint64 `json:"misses"`
+	Evictions uint64 `json:"evictions"`
+	Entries   int    `json:"entries"`
+}
+
+// Cache é um Provider que guarda em memória as respostas de outro Provider.
+// É seguro para uso concorrente.
+type Cache struct {
+	upstream Provider
+	opts     CacheOptions
+	now      func() time.Time // Substituído nos testes
+
+	mu    sync.Mutex
+	order *list.List               // Frente = usado mais recentemente
+	items map[string]*list.Element // cep ==> elemento de order (*cacheEntry)
+
+	hits, misses, evictions atomic.Uint64
+}
+
+// cacheEntry é um item do cache; também é o formato salvo no snapshot em disco
+type cacheEntry struct {
+	Cep       string    `json:"cep"`
+	Address   *ViaCEP   `json:"address,omitempty"`
+	NotFound  bool      `json:"not_found,omitempty"`
+	StoredAt  time.Time `json:"stored_at"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// cacheSnapshot é o conteúdo do arquivo JSON gerado por SaveFile
+type cacheSnapshot struct {
+	Version int          `json:"version"`
+	Entries []cacheEntry `json:"entries"`
+}
+
+// NewCache cria um Cache na frente do upstream
+func NewCache(upstream Provider, opts CacheOptions) *Cache {
+	if opts.MaxEntries <= 0 {
+		opts.MaxEntries = 10000
+	}
+	if opts.TTL <= 0 {
+		opts.TTL = 24 * time.Hour
+	}
+	if opts.NegativeTTL <= 0 {
+		opts.NegativeTTL = 10 * time.Minute
+	}
+	return &Cache{
+		upstream: upstream,
+		opts:     opts,
+		now:      time.Now,
+		order:    list.New(),
+		items:    make(map[string]*list.Element),
+	}
+}
+
+func (c *Cache) Name() string { return "cache(" + c.upstream.Name() + ")" }
+
+// Lookup devolve o endereço do cache ou consulta o upstream em caso de miss.
+// CEPs inexistentes também são guardados (cache negativo) e devolvem ErrCEPNotFound.
+func (c *Cache) Lookup(ctx context.Context, cep string) (*ViaCEP, error) {
+	if entry, ok := c.get(cep); ok {
+		c.hits.Add(1)
+		if entry.NotFound {
+			return nil, ErrCEPNotFound
+		}
+		return copyAddress(entry.Address), nil
+	}
+	c.misses.Add(1)
+
+	addr, err := c.upstream.Lookup(ctx, cep)
+	switch {
+	case err == nil:
+		c.put(cacheEntry{Cep: cep, Address: copyAddress(addr)}, c.opts.TTL)
+	case errors.Is(err, ErrCEPNotFound):
+		c.put(cacheEntry{Cep: cep, NotFound: true}, c.opts.NegativeTTL)
+	}
+	return addr, err
+}
+
+// Stale devolve o endereço guardado mesmo que já tenha expirado.
+// Serve para responder com dado antigo quando o upstream está fora do ar.
+func (c *Cache) Stale(cep string) (*ViaCEP, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	elem, ok := c.items[cep]
+	if !ok {
+		return nil, false
+	}
+	entry := elem.Value.(*cacheEntry)
+	if entry.NotFound {
+		return nil, false
+	}
+	return copyAddress(entry.Address), true
+}
+
+// Stats devolve os contadores de hit/miss e o tamanho atual
+func (c *Cache) Stats() CacheStats {
+	c.mu.Lock()
+	entries := c.order.Len()
+	c.mu.Unlock()
+
+	return CacheStats{
+		Hits:      c.hits.Load(),
+		Misses:    c.misses.Load(),
+		Evictions: c.evictions.Load(),
+		Entries:   entries,
+	}
+}
+
+// get devolve a entrada válida (não expirada) e marca como usada recentemente.
+// Entradas expiradas continuam na lista até serem substituídas ou despejadas.
+func (c *Cache) get(cep string) (cacheEntry, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	elem, ok := c.items[cep]
+	if !ok {
+		return cacheEntry{}, false
+	}
+	entry := elem.Value.(*cacheEntry)
+	if !c.now().Before(entry.ExpiresAt) {
+		return cacheEntry{}, false
+	}
+	c.order.MoveToFront(elem)
+	return *entry, true
+}
+
+// put grava a entrada com a validade informada
+func (c *Cache) put(entry cacheEntry, ttl time.Duration) {
+	entry.StoredAt = c.now()
+	entry.ExpiresAt = entry.StoredAt.Add(ttl)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.insert(&entry)
+}
+
+// insert adiciona/substitui a entrada e despeja as menos usadas (chamar com mu travado)
+func (c *Cache) insert(entry *cacheEntry) {
+	if elem, ok := c.items[entry.Cep]; ok {
+		elem.Value = entry
+		c.order.MoveToFront(elem)
+		return
+	}
+	c.items[entry.Cep] = c.order.PushFront(entry)
+
+	for c.order.Len() > c.opts.MaxEntries {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.items, oldest.Value.(*cacheEntry).Cep)
+		c.evictions.Add(1)
+	}
+}
+
+// ############################## SNAPSHOT #####################################
+
+// SaveFile grava as entradas válidas em um arquivo JSON (ex.: no shutdown).
+// A escrita é atômica: arquivo temporário + rename.
+func (c *Cache) SaveFile(path string) error {
+	now := c.now()
+	snapshot := cacheSnapshot{Version: 1}
+
+	c.mu.Lock()
+	for elem := c.order.Front(); elem != nil; elem = elem.Next() {
+		entry := elem.Value.(*cacheEntry)
+		if now.Before(entry.ExpiresAt) {
+			snapshot.Entries = append(snapshot.Entries, *entry)
+		}
+	}
+	c.mu.Unlock()
+
+	data, err := json.MarshalIndent(snapshot, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name()) // Sem efeito após o rename
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// LoadFile carrega um snapshot gerado por SaveFile (ex.: na inicialização).
+// Arquivo inexistente não é erro; entradas já expiradas são descartadas.
+func (c *Cache) LoadFile(path string) error {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	var snapshot cacheSnapshot
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		return err
+	}
+
+	now := c.now()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	// O snapshot está do mais recente para o mais antigo: insere de trás para frente
+	for i := len(snapshot.Entries) - 1; i >= 0; i-- {
+		entry := snapshot.Entries[i]
+		if entry.Cep == "" || !now.Before(entry.ExpiresAt) {
+			continue
+		}
+		if !entry.NotFound && entry.Address == nil {
+			continue
+		}
+		c.insert(&entry)
+	}
+	return nil
+}
+
+// copyAddress evita que quem recebe o endereço altere o valor guardado no cache
+func copyAddress(addr *ViaCEP) *ViaCEP {
+	if addr == nil {
+		return nil
+	}
+	clone := *addr
+	return &clone
+}
@@ -0,0 +1,191 @@
+package getCep
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// countingProvider conta as chamadas e devolve ErrCEPNotFound para os CEPs em missing
+type countingProvider struct {
+	calls   atomic.Int32
+	missing map[string]bool
+}
+
+func (p *countingProvider) Name() string { return "counting" }
+
+func (p *countingProvider) Lookup(ctx context.Context, cep string) (*ViaCEP, error) {
+	p.calls.Add(1)
+	if p.missing[cep] {
+		return nil, ErrCEPNotFound
+	}
+	return &ViaCEP{Cep: cep, Localidade: "São Paulo"}, nil
+}
+
+// fakeClock permite avançar o tempo nos testes de TTL
+type fakeClock struct{ t time.Time }
+
+func (c *fakeClock) Now() time.Time          { return c.t }
+func (c *fakeClock) Advance(d time.Duration) { c.t = c.t.Add(d) }
+
+func newTestCache(upstream Provider, opts CacheOptions) (*Cache, *fakeClock) {
+	clock := &fakeClock{t: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)}
+	c := NewCache(upstream, opts)
+	c.now = clock.Now
+	return c, clock
+}
+
+func TestCacheHitMiss(t *testing.T) {
+	upstream := &countingProvider{}
+	c, _ := newTestCache(upstream, CacheOptions{})
+	ctx := context.Background()
+
+	for range 3 {
+		if _, err := c.Lookup(ctx, "01001000"); err != nil {
+			t.Fatalf("Lookup() erro inesperado: %v", err)
+		}
+	}
+
+	if got := upstream.calls.Load(); got != 1 {
+		t.Errorf("chamadas ao upstream = %d; expect 1", got)
+	}
+	stats := c.Stats()
+	if stats.Hits != 2 || stats.Misses != 1 || stats.Entries != 1 {
+		t.Errorf("Stats() = %+v; expect 2 hits, 1 miss, 1 entrada", stats)
+	}
+}
+
+func TestCacheReturnsCopy(t *testing.T) {
+	c, _ := newTestCache(&countingProvider{}, CacheOptions{})
+	ctx := context.Background()
+
+	addr, _ := c.Lookup(ctx, "01001000")
+	addr.Localidade = "alterado"
+
+	again, _ := c.Lookup(ctx, "01001000")
+	if again.Localidade != "São Paulo" {
+		t.Errorf("Localidade = %q; o cache não deve ser alterado por quem recebe o endereço", again.Localidade)
+	}
+}
+
+func TestCacheTTL(t *testing.T) {
+	upstream := &countingProvider{missing: map[string]bool{"01009999": true}}
+	c, clock := newTestCache(upstream, CacheOptions{TTL: time.Hour, NegativeTTL: time.Minute})
+	ctx := context.Background()
+
+	c.Lookup(ctx, "01001000")
+	if _, err := c.Lookup(ctx, "01009999"); !errors.Is(err, ErrCEPNotFound) {
+		t.Fatalf("Lookup() erro = %v; expect %v", err, ErrCEPNotFound)
+	}
+
+	// Cache negativo: o segundo "não encontrado" vem do cache
+	if _, err := c.Lookup(ctx, "01009999"); !errors.Is(err, ErrCEPNotFound) {
+		t.Fatalf("Lookup() erro = %v; expect %v", err, ErrCEPNotFound)
+	}
+	if got := upstream.calls.Load(); got != 2 {
+		t.Fatalf("chamadas ao upstream = %d; expect 2", got)
+	}
+
+	// Depois de 2 minutos só a entrada negativa expirou
+	clock.Advance(2 * time.Minute)
+	c.Lookup(ctx, "01001000")
+	c.Lookup(ctx, "01009999")
+	if got := upstream.calls.Load(); got != 3 {
+		t.Fatalf("chamadas ao upstream = %d; expect 3", got)
+	}
+
+	// Depois de 1 hora a entrada positiva também expirou
+	clock.Advance(time.Hour)
+	c.Lookup(ctx, "01001000")
+	if got := upstream.calls.Load(); got != 4 {
+		t.Fatalf("chamadas ao upstream = %d; expect 4", got)
+	}
+}
+
+func TestCacheLRUEviction(t *testing.T) {
+	upstream := &countingProvider{}
+	c, _ := newTestCache(upstream, CacheOptions{MaxEntries: 2})
+	ctx := context.Background()
+
+	c.Lookup(ctx, "01001000") // A
+	c.Lookup(ctx, "01002000") // B
+	c.Lookup(ctx, "01001000") // A vira o mais recente
+	c.Lookup(ctx, "01003000") // C despeja B
+
+	calls := upstream.calls.Load()
+	c.Lookup(ctx, "01001000") // A continua no cache
+	if got := upstream.calls.Load(); got != calls {
+		t.Errorf("A foi despejado, mas era o mais recente")
+	}
+	c.Lookup(ctx, "01002000") // B precisa ir ao upstream
+	if got := upstream.calls.Load(); got != calls+1 {
+		t.Errorf("B deveria ter sido despejado")
+	}
+	if stats := c.Stats(); stats.Entries != 2 || stats.Evictions != 2 {
+		t.Errorf("Stats() = %+v; expect 2 entradas e 2 despejos", stats)
+	}
+}
+
+func TestCacheSnapshot(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cache.json")
+	upstream := &countingProvider{missing: map[string]bool{"01009999": true}}
+	c, clock := newTestCache(upstream, CacheOptions{TTL: time.Hour})
+	ctx := context.Background()
+
+	c.Lookup(ctx, "01001000")
+	c.Lookup(ctx, "01009999")
+	if err := c.SaveFile(path); err != nil {
+		t.Fatalf("SaveFile() erro inesperado: %v", err)
+	}
+
+	restored, restoredClock := newTestCache(upstream, CacheOptions{TTL: time.Hour})
+	restoredClock.t = clock.t.Add(time.Minute)
+	if err := restored.LoadFile(path); err != nil {
+		t.Fatalf("LoadFile() erro inesperado: %v", err)
+	}
+
+	calls := upstream.calls.Load()
+	addr, err := restored.Lookup(ctx, "01001000")
+	if err != nil || addr.Localidade != "São Paulo" {
+		t.Fatalf("Lookup() = %+v, %v; expect endereço do snapshot", addr, err)
+	}
+	if _, err := restored.Lookup(ctx, "01009999"); !errors.Is(err, ErrCEPNotFound) {
+		t.Fatalf("Lookup() erro = %v; expect %v", err, ErrCEPNotFound)
+	}
+	if got := upstream.calls.Load(); got != calls {
+		t.Errorf("chamadas ao upstream = %d; expect %d (tudo veio do snapshot)", got, calls)
+	}
+
+	// Arquivo inexistente não é erro
+	if err := restored.LoadFile(filepath.Join(t.TempDir(), "nao-existe.json")); err != nil {
+		t.Errorf("LoadFile() de arquivo inexistente: %v", err)
+	}
+}
+
+// go test -race ./1_moduleFoundation/5_cep-handler/getCep -run TestCacheConcurrent
+func TestCacheConcurrent(t *testing.T) {
+	c := NewCache(&countingProvider{}, CacheOptions{MaxEntries: 50})
+	ctx := context.Background()
+
+	var wg sync.WaitGroup
+	for i := range 20 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := range 100 {
+				c.Lookup(ctx, fmt.Sprintf("01%03d%03d", i, j%60))
+				c.Stats()
+			}
+		}()
+	}
+	wg.Wait()
+
+	if stats := c.Stats(); stats.Entries > 50 || stats.Hits+stats.Misses != 2000 {
+		t.Errorf("Stats() = %+v; expect no máximo 50 entradas e 2000 consultas", stats)
+	}
+}
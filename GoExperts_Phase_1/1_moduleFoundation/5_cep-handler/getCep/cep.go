@@ -0,0 +1,53 @@
+package getCep
+
+import (
+	"fmt"
+	"strings"
+)
+
+// menorCEP é o primeiro CEP da faixa dos Correios (01000-000, São Paulo).
+// Tudo abaixo disso (ex.: 00000-000, 00999-999) não existe.
+const menorCEP = "01000000"
+
+// Normalize remove a máscara (hífen, ponto e espaços) e valida o CEP.
+// Devolve sempre 8 dígitos ("01001000") ou um erro que embrulha ErrInvalidCEP,
+// sem fazer nenhuma chamada de rede.
+func Normalize(cep string) (string, error) {
+	input := strings.TrimSpace(cep)
+	if input == "" {
+		return "", fmt.Errorf("%w: cep vazio", ErrInvalidCEP)
+	}
+
+	var digits strings.Builder
+	for i, r := range input {
+		switch {
+		case r >= '0' && r <= '9':
+			digits.WriteRune(r)
+		case r == '-' || r == '.' || r == ' ':
+			// Caracteres de máscara: ignorados
+		default:
+			return "", fmt.Errorf("%w: %q contém caractere não permitido %q na posição %d", ErrInvalidCEP, cep, r, i)
+		}
+	}
+
+	normalized := digits.String()
+	if len(normalized) != 8 {
+		return "", fmt.Errorf("%w: %q deve ter 8 dígitos, tem %d", ErrInvalidCEP, cep, len(normalized))
+	}
+	if normalized < menorCEP {
+		return "", fmt.Errorf("%w: %q está fora da faixa de CEPs (a partir de 01000-000)", ErrInvalidCEP, cep)
+	}
+	if strings.Count(normalized, normalized[:1]) == 8 {
+		return "", fmt.Errorf("%w: %q é uma sequência repetida, não um CEP real", ErrInvalidCEP, cep)
+	}
+	return normalized, nil
+}
+
+// Format normaliza o CEP e devolve no formato "00000-000"
+func Format(cep string) (string, error) {
+	normalized, err := Normalize(cep)
+	if err != nil {
+		return "", err
+	}
+	return normalized[:5] + "-" + normalized[5:], nil
+}
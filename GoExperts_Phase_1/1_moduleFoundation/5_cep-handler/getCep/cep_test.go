@@ -0,0 +1,111 @@
+package getCep
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		expect  string
+		invalid bool
+	}{
+		{name: "somente dígitos", input: "01001000", expect: "01001000"},
+		{name: "com hífen", input: "01001-000", expect: "01001000"},
+		{name: "com ponto e hífen", input: "01.001-000", expect: "01001000"},
+		{name: "espaços nas pontas", input: "  14093-070 ", expect: "14093070"},
+		{name: "espaço interno", input: "14093 070", expect: "14093070"},
+		{name: "vazio", input: "", invalid: true},
+		{name: "só espaços", input: "   ", invalid: true},
+		{name: "letras", input: "0100100a", invalid: true},
+		{name: "caminho na url", input: "01001000/../", invalid: true},
+		{name: "query string", input: "01001000?x=1", invalid: true},
+		{name: "curto", input: "0100100", invalid: true},
+		{name: "longo", input: "010010000", invalid: true},
+		{name: "abaixo da faixa", input: "00999-999", invalid: true},
+		{name: "zeros", input: "00000-000", invalid: true},
+		{name: "sequência repetida", input: "11111111", invalid: true},
+		{name: "dígito unicode", input: "0100100٣", invalid: true},
+	}
+
+	for _, item := range tests {
+		t.Run(item.name, func(t *testing.T) {
+			result, err := Normalize(item.input)
+			if item.invalid {
+				if !errors.Is(err, ErrInvalidCEP) {
+					t.Errorf("Normalize(%q) erro = %v; expect %v", item.input, err, ErrInvalidCEP)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Normalize(%q) erro inesperado: %v", item.input, err)
+			}
+			if result != item.expect {
+				t.Errorf("Normalize(%q) = %q; expect %q", item.input, result, item.expect)
+			}
+		})
+	}
+}
+
+func TestFormat(t *testing.T) {
+	tests := []struct {
+		input  string
+		expect string
+	}{
+		{"01001000", "01001-000"},
+		{"01001-000", "01001-000"},
+		{"14.093-070", "14093-070"},
+	}
+
+	for _, item := range tests {
+		t.Run(item.input, func(t *testing.T) {
+			result, err := Format(item.input)
+			if err != nil {
+				t.Fatalf("Format(%q) erro inesperado: %v", item.input, err)
+			}
+			if result != item.expect {
+				t.Errorf("Format(%q) = %q; expect %q", item.input, result, item.expect)
+			}
+		})
+	}
+}
+
+// go test ./1_moduleFoundation/5_cep-handler/getCep -fuzz=FuzzNormalize -fuzztime=10s -run=^$
+func FuzzNormalize(f *testing.F) {
+	f.Add("01001000")
+	f.Add("01001-000")
+	f.Add(" 14.093-070 ")
+	f.Add("00000000")
+	f.Add("abc")
+	f.Add("01001000/json/../")
+
+	f.Fuzz(func(t *testing.T, input string) {
+		got, err := Normalize(input)
+		if err != nil {
+			if !errors.Is(err, ErrInvalidCEP) {
+				t.Fatalf("Normalize(%q) devolveu erro fora de ErrInvalidCEP: %v", input, err)
+			}
+			return
+		}
+
+		// Sucesso: exatamente 8 dígitos ASCII, dentro da faixa
+		if len(got) != 8 || strings.Trim(got, "0123456789") != "" {
+			t.Fatalf("Normalize(%q) = %q; esperado 8 dígitos", input, got)
+		}
+		if got < menorCEP {
+			t.Fatalf("Normalize(%q) = %q; abaixo de %s", input, got, menorCEP)
+		}
+
+		// Idempotente e estável após Format
+		if again, err := Normalize(got); err != nil || again != got {
+			t.Fatalf("Normalize(%q) = %q, %v; esperado %q", got, again, err, got)
+		}
+		formatted, err := Format(input)
+		if err != nil || formatted != got[:5]+"-"+got[5:] {
+			t.Fatalf("Format(%q) = %q, %v", input, formatted, err)
+		}
+	})
+}
@@ -0,0 +1,12 @@
+package getCep
+
+import "errors"
+
+// Erros sentinela devolvidos por GetCep e pelos providers.
+// Use errors.Is(err, getCep.ErrCEPNotFound) para identificar cada caso.
+var (
+	ErrInvalidCEP          = errors.New("cep inválido")
+	ErrCEPNotFound         = errors.New("cep não encontrado")
+	ErrUpstreamUnavailable = errors.New("serviço de cep indisponível")
+	ErrInvalidSearch       = errors.New("busca de endereço inválida")
+)
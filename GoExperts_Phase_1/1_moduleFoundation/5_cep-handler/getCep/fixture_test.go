@@ -0,0 +1,119 @@
+package getCep
+
+import (
+	"GoProject/1_moduleFoundation/2_HTTPClient/cassette"
+	"GoProject/1_moduleFoundation/2_HTTPClient/cassette/cassettetest"
+	"context"
+	"errors"
+	"net/http"
+	"path/filepath"
+	"testing"
+)
+
+// Testes com respostas da ViaCEP/BrasilAPI em testdata/fixtures: arquivos no
+// formato do cassette escritos à mão (não são gravações do serviço real), só
+// reproduzidos. O modo fica fixo em replay para CASSETTE_MODE=record não
+// sobrescrever as fixtures, que são compartilhadas entre os testes.
+
+// fixtureClient devolve um http.Client que responde a partir da fixture informada
+func fixtureClient(t *testing.T, name string) *http.Client {
+	t.Helper()
+	rec := cassettetest.New(t, filepath.Join("testdata", "fixtures", name+".json"), cassette.Options{Mode: cassette.ModeReplay})
+	return &http.Client{Transport: rec}
+}
+
+func TestViaCEPFixture(t *testing.T) {
+	p := NewViaCEPProvider()
+	p.Client = fixtureClient(t, "viacep")
+
+	tests := []struct {
+		cep    string
+		expect ViaCEP
+		err    error
+	}{
+		{"01001000", ViaCEP{Cep: "01001-000", Logradouro: "Praça da Sé", Complemento: "lado ímpar", Bairro: "Sé", Localidade: "São Paulo", Uf: "SP", Estado: "São Paulo", Regiao: "Sudeste", Ibge: "3550308", Gia: "1004", Ddd: "11", Siafi: "7107"}, nil},
+		{"14093070", ViaCEP{Cep: "14093-070", Logradouro: "Rua Capitão Adelmio Norberto da Silva", Bairro: "Ribeirânia", Localidade: "Ribeirão Preto", Uf: "SP", Estado: "São Paulo", Regiao: "Sudeste", Ibge: "3543402", Gia: "5824", Ddd: "16", Siafi: "6969"}, nil},
+		{"01009999", ViaCEP{}, ErrCEPNotFound}, // ViaCEP responde 200 com {"erro": "true"}
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.cep, func(t *testing.T) {
+			got, err := p.Lookup(context.Background(), tt.cep)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("Lookup(%q) erro = %v; expect %v", tt.cep, err, tt.err)
+			}
+			if err == nil && *got != tt.expect {
+				t.Errorf("Lookup(%q) = %+v; expect %+v", tt.cep, *got, tt.expect)
+			}
+		})
+	}
+}
+
+func TestBrasilAPIFixture(t *testing.T) {
+	p := NewBrasilAPIProvider()
+	p.Client = fixtureClient(t, "brasilapi")
+
+	got, err := p.Lookup(context.Background(), "01001000")
+	if err != nil {
+		t.Fatalf("Lookup() erro inesperado: %v", err)
+	}
+	expect := ViaCEP{Cep: "01001-000", Logradouro: "Praça da Sé", Bairro: "Sé", Localidade: "São Paulo", Uf: "SP"}
+	if *got != expect {
+		t.Errorf("Lookup() = %+v; expect %+v", *got, expect)
+	}
+
+	// A BrasilAPI responde 404 com um JSON de erro
+	if _, err := p.Lookup(context.Background(), "01009999"); !errors.Is(err, ErrCEPNotFound) {
+		t.Errorf("Lookup() erro = %v; expect %v", err, ErrCEPNotFound)
+	}
+}
+
+func TestResolverFixture(t *testing.T) {
+	viaCEP := NewViaCEPProvider()
+	viaCEP.Client = fixtureClient(t, "viacep")
+	brasilAPI := NewBrasilAPIProvider()
+	brasilAPI.Client = fixtureClient(t, "brasilapi")
+	resolver := NewResolver(viaCEP, brasilAPI)
+
+	got, err := resolver.Lookup(context.Background(), "01001000")
+	if err != nil {
+		t.Fatalf("Lookup() erro inesperado: %v", err)
+	}
+	if got.Cep != "01001-000" || got.Localidade != "São Paulo" {
+		t.Errorf("Lookup() = %+v; expect 01001-000 em São Paulo", *got)
+	}
+
+	if _, err := resolver.Lookup(context.Background(), "01009999"); !errors.Is(err, ErrCEPNotFound) {
+		t.Errorf("Lookup() erro = %v; expect %v", err, ErrCEPNotFound)
+	}
+}
+
+func TestSearchFixture(t *testing.T) {
+	p := NewViaCEPProvider()
+	p.Client = fixtureClient(t, "search")
+
+	tests := []struct {
+		logradouro string
+		expect     []string
+	}{
+		{"Avenida Paulista", []string{"01310-100", "01310-200"}},
+		{"Rua Que Não Existe", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.logradouro, func(t *testing.T) {
+			results, err := p.Search(context.Background(), "SP", "São Paulo", tt.logradouro)
+			if err != nil {
+				t.Fatalf("Search() erro inesperado: %v", err)
+			}
+			if len(results) != len(tt.expect) {
+				t.Fatalf("Search() = %d resultados; expect %d", len(results), len(tt.expect))
+			}
+			for i, cep := range tt.expect {
+				if results[i].Cep != cep {
+					t.Errorf("results[%d].Cep = %q; expect %q", i, results[i].Cep, cep)
+				}
+			}
+		})
+	}
+}
@@ -1,10 +1,6 @@
 package getCep
 
-import (
-	"encoding/json"
-	"io"
-	"net/http"
-)
+import "context"
 
 type ViaCEP struct {
 	Cep         string `json:"cep"`
@@ -22,23 +18,19 @@ type ViaCEP struct {
 	Siafi       string `json:"siafi"`
 }
 
-func GetCepFunc(cep string) (*ViaCEP, error) {
-	resp, error := http.Get("https://viacep.com.br/ws/" + cep + "/json/")
-	if error != nil {
-		return nil, error
-	}
-	defer resp.Body.Close()
-
-	body, error := io.ReadAll(resp.Body)
-	if error != nil {
-		return nil, error
-	}
-
-	var responseData ViaCEP
-	error = json.Unmarshal(body, &responseData) // Parse para o formato da struct
-	if error != nil {
-		return nil, error
+// GetCep busca o endereço usando o DefaultProvider (ViaCEP + BrasilAPI em paralelo).
+// O CEP é validado com Normalize antes de qualquer chamada de rede.
+// Respeita o cancelamento do ctx e devolve ErrInvalidCEP, ErrCEPNotFound ou
+// ErrUpstreamUnavailable (use errors.Is para diferenciar).
+func GetCep(ctx context.Context, cep string) (*ViaCEP, error) {
+	normalized, err := Normalize(cep)
+	if err != nil {
+		return nil, err
 	}
+	return DefaultProvider.Lookup(ctx, normalized)
+}
 
-	return &responseData, nil
+// GetCepFunc é a versão sem context de GetCep
+func GetCepFunc(cep string) (*ViaCEP, error) {
+	return GetCep(context.Background(), cep)
 }
@@ -0,0 +1,208 @@
+package getCep
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strconv"
+)
+
+// Formato do índice offline (little endian):
+//
+//	"CEPIDX01"                      8 bytes
+//	N                               uint32
+//	N x (cep uint32, offset uint32) ordenado por cep, para busca binária
+//	registros                       12 campos por CEP, cada um uvarint(tamanho) + bytes
+//
+// O CEP vira um uint32 (8 dígitos cabem) e não é repetido nos registros.
+const indexMagic = "CEPIDX01"
+
+// ErrInvalidIndex indica um arquivo que não é um índice válido
+var ErrInvalidIndex = errors.New("índice offline inválido")
+
+// OfflineIndex é um Provider que responde a partir de um índice local,
+// gerado com WriteIndex (ver cmd/importcep). Não faz chamadas de rede.
+type OfflineIndex struct {
+	keys    []uint32 // CEPs ordenados
+	offsets []uint32 // Início do registro de keys[i] em data
+	data    []byte
+}
+
+// LoadIndex carrega o arquivo de índice em memória
+func LoadIndex(path string) (*OfflineIndex, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return ReadIndex(data)
+}
+
+// ReadIndex interpreta o conteúdo de um índice gerado por WriteIndex
+func ReadIndex(data []byte) (*OfflineIndex, error) {
+	header := len(indexMagic) + 4
+	if len(data) < header || string(data[:len(indexMagic)]) != indexMagic {
+		return nil, fmt.Errorf("%w: cabeçalho desconhecido", ErrInvalidIndex)
+	}
+
+	count := int(binary.LittleEndian.Uint32(data[len(indexMagic):]))
+	tableEnd := header + count*8
+	if tableEnd > len(data) {
+		return nil, fmt.Errorf("%w: tabela truncada", ErrInvalidIndex)
+	}
+
+	ix := &OfflineIndex{
+		keys:    make([]uint32, count),
+		offsets: make([]uint32, count),
+		data:    data[tableEnd:],
+	}
+	for i := range count {
+		pos := header + i*8
+		ix.keys[i] = binary.LittleEndian.Uint32(data[pos:])
+		ix.offsets[i] = binary.LittleEndian.Uint32(data[pos+4:])
+		if int(ix.offsets[i]) > len(ix.data) || (i > 0 && ix.keys[i] <= ix.keys[i-1]) {
+			return nil, fmt.Errorf("%w: entrada %d corrompida", ErrInvalidIndex, i)
+		}
+	}
+	return ix, nil
+}
+
+func (ix *OfflineIndex) Name() string { return "offline" }
+
+// Len devolve a quantidade de CEPs no índice
+func (ix *OfflineIndex) Len() int { return len(ix.keys) }
+
+func (ix *OfflineIndex) Lookup(ctx context.Context, cep string) (*ViaCEP, error) {
+	normalized, err := Normalize(cep)
+	if err != nil {
+		return nil, err
+	}
+	key, _ := strconv.ParseUint(normalized, 10, 32)
+
+	i, found := slices.BinarySearch(ix.keys, uint32(key))
+	if !found {
+		return nil, ErrCEPNotFound
+	}
+
+	fields, err := decodeFields(ix.data[ix.offsets[i]:])
+	if err != nil {
+		return nil, err
+	}
+	addr := fromFields(fields)
+	addr.Cep = normalized[:5] + "-" + normalized[5:]
+	return addr, nil
+}
+
+// WriteIndex grava os endereços no formato do índice offline.
+// CEPs inválidos são ignorados; em CEPs repetidos vale o último.
+// Devolve quantos CEPs foram gravados.
+func WriteIndex(w io.Writer, addrs []ViaCEP) (int, error) {
+	byKey := make(map[uint32]ViaCEP, len(addrs))
+	for _, addr := range addrs {
+		normalized, err := Normalize(addr.Cep)
+		if err != nil {
+			continue
+		}
+		key, _ := strconv.ParseUint(normalized, 10, 32)
+		byKey[uint32(key)] = addr
+	}
+
+	keys := make([]uint32, 0, len(byKey))
+	for key := range byKey {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+
+	// Monta a área de registros primeiro para conhecer os offsets
+	var records []byte
+	offsets := make([]uint32, len(keys))
+	for i, key := range keys {
+		offsets[i] = uint32(len(records))
+		for _, field := range toFields(byKey[key]) {
+			records = binary.AppendUvarint(records, uint64(len(field)))
+			records = append(records, field...)
+		}
+	}
+
+	out := bufio.NewWriter(w)
+	out.WriteString(indexMagic)
+	binary.Write(out, binary.LittleEndian, uint32(len(keys)))
+	for i, key := range keys {
+		binary.Write(out, binary.LittleEndian, key)
+		binary.Write(out, binary.LittleEndian, offsets[i])
+	}
+	out.Write(records)
+	return len(keys), out.Flush()
+}
+
+// toFields/fromFields definem a ordem dos campos no registro (sem o CEP)
+func toFields(a ViaCEP) []string {
+	return []string{a.Logradouro, a.Complemento, a.Unidade, a.Bairro, a.Localidade, a.Uf, a.Estado, a.Regiao, a.Ibge, a.Gia, a.Ddd, a.Siafi}
+}
+
+func fromFields(f []string) *ViaCEP {
+	return &ViaCEP{
+		Logradouro: f[0], Complemento: f[1], Unidade: f[2], Bairro: f[3], Localidade: f[4], Uf: f[5],
+		Estado: f[6], Regiao: f[7], Ibge: f[8], Gia: f[9], Ddd: f[10], Siafi: f[11],
+	}
+}
+
+// indexFields é a quantidade de campos gravados por registro
+const indexFields = 12
+
+func decodeFields(data []byte) ([]string, error) {
+	fields := make([]string, indexFields)
+	for i := range fields {
+		size, n := binary.Uvarint(data)
+		if n <= 0 || uint64(len(data)-n) < size {
+			return nil, fmt.Errorf("%w: registro truncado", ErrInvalidIndex)
+		}
+		fields[i] = string(data[n : n+int(size)])
+		data = data[n+int(size):]
+	}
+	return fields, nil
+}
+
+// ############################## FALLBACK #####################################
+
+// fallbackProvider usa o secondary quando o primary está indisponível
+type fallbackProvider struct {
+	primary, secondary Provider
+}
+
+// Fallback devolve um Provider que consulta o primary e, se ele estiver
+// indisponível (ErrUpstreamUnavailable, ErrCircuitOpen ou timeout), responde
+// com o secondary (ex.: o índice offline). CEP inválido ou não encontrado
+// no primary não aciona o fallback.
+func Fallback(primary, secondary Provider) Provider {
+	return &fallbackProvider{primary: primary, secondary: secondary}
+}
+
+func (p *fallbackProvider) Name() string {
+	return p.primary.Name() + "|" + p.secondary.Name()
+}
+
+func (p *fallbackProvider) Lookup(ctx context.Context, cep string) (*ViaCEP, error) {
+	addr, err := p.primary.Lookup(ctx, cep)
+	if err == nil || !isUnavailable(err) || errors.Is(ctx.Err(), context.Canceled) {
+		return addr, err
+	}
+
+	// O ctx do primary pode ter estourado o prazo: o índice local é instantâneo
+	fallback, fallbackErr := p.secondary.Lookup(context.WithoutCancel(ctx), cep)
+	if fallbackErr != nil {
+		return nil, err // Mantém o erro original: o índice local pode estar incompleto
+	}
+	return fallback, nil
+}
+
+// isUnavailable diz se o erro é de indisponibilidade (e não uma resposta do upstream)
+func isUnavailable(err error) bool {
+	return errors.Is(err, ErrUpstreamUnavailable) ||
+		errors.Is(err, ErrCircuitOpen) ||
+		errors.Is(err, context.DeadlineExceeded)
+}
@@ -0,0 +1,135 @@
+package getCep
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func newTestIndex(t *testing.T, addrs []ViaCEP) *OfflineIndex {
+	t.Helper()
+	var buf bytes.Buffer
+	if _, err := WriteIndex(&buf, addrs); err != nil {
+		t.Fatalf("WriteIndex() erro inesperado: %v", err)
+	}
+	ix, err := ReadIndex(buf.Bytes())
+	if err != nil {
+		t.Fatalf("ReadIndex() erro inesperado: %v", err)
+	}
+	return ix
+}
+
+func TestOfflineIndexLookup(t *testing.T) {
+	addrs := []ViaCEP{
+		{Cep: "01001-000", Logradouro: "Praça da Sé", Complemento: "lado ímpar", Bairro: "Sé", Localidade: "São Paulo", Uf: "SP", Estado: "São Paulo", Regiao: "Sudeste", Ibge: "3550308", Gia: "1004", Ddd: "11", Siafi: "7107"},
+		{Cep: "20040020", Logradouro: "Avenida Rio Branco", Localidade: "Rio de Janeiro", Uf: "RJ"},
+		{Cep: "123", Logradouro: "inválido"},                       // Ignorado
+		{Cep: "20040-020", Logradouro: "Av. Rio Branco", Uf: "RJ"}, // Repetido: vale o último
+	}
+
+	var buf bytes.Buffer
+	written, err := WriteIndex(&buf, addrs)
+	if err != nil || written != 2 {
+		t.Fatalf("WriteIndex() = %d, %v; expect 2, nil", written, err)
+	}
+	ix, err := ReadIndex(buf.Bytes())
+	if err != nil {
+		t.Fatalf("ReadIndex() erro inesperado: %v", err)
+	}
+
+	tests := []struct {
+		cep    string
+		expect *ViaCEP
+		err    error
+	}{
+		{"01001000", &addrs[0], nil},
+		{"20040-020", &ViaCEP{Cep: "20040-020", Logradouro: "Av. Rio Branco", Uf: "RJ"}, nil},
+		{"01310100", nil, ErrCEPNotFound},
+		{"abc", nil, ErrInvalidCEP},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.cep, func(t *testing.T) {
+			got, err := ix.Lookup(context.Background(), tt.cep)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("Lookup(%q) erro = %v; expect %v", tt.cep, err, tt.err)
+			}
+			if tt.expect != nil && *got != *tt.expect {
+				t.Errorf("Lookup(%q) = %+v; expect %+v", tt.cep, *got, *tt.expect)
+			}
+		})
+	}
+}
+
+func TestReadIndexInvalid(t *testing.T) {
+	var buf bytes.Buffer
+	WriteIndex(&buf, []ViaCEP{{Cep: "01001000", Logradouro: "Praça da Sé"}})
+	valid := buf.Bytes()
+
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{"vazio", nil},
+		{"outro formato", []byte(`{"cep":"01001000"}`)},
+		{"tabela truncada", valid[:len(indexMagic)+6]},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ReadIndex(tt.data); !errors.Is(err, ErrInvalidIndex) {
+				t.Errorf("ReadIndex() erro = %v; expect %v", err, ErrInvalidIndex)
+			}
+		})
+	}
+
+	// Registro cortado só aparece na busca
+	ix, err := ReadIndex(valid[:len(valid)-3])
+	if err != nil {
+		t.Fatalf("ReadIndex() erro inesperado: %v", err)
+	}
+	if _, err := ix.Lookup(context.Background(), "01001000"); !errors.Is(err, ErrInvalidIndex) {
+		t.Errorf("Lookup() erro = %v; expect %v", err, ErrInvalidIndex)
+	}
+}
+
+func TestFallback(t *testing.T) {
+	index := newTestIndex(t, []ViaCEP{{Cep: "01001000", Logradouro: "Praça da Sé"}})
+
+	tests := []struct {
+		name       string
+		primaryErr error
+		cep        string
+		expectErr  error
+		expectFrom string // "primary", "offline" ou "" (erro)
+	}{
+		{"primary ok", nil, "01001000", nil, "primary"},
+		{"upstream fora", ErrUpstreamUnavailable, "01001000", nil, "offline"},
+		{"circuito aberto", &CircuitOpenError{}, "01001000", nil, "offline"},
+		{"timeout", fmt.Errorf("viacep: %w", context.DeadlineExceeded), "01001000", nil, "offline"},
+		{"não encontrado não usa fallback", ErrCEPNotFound, "01001000", ErrCEPNotFound, ""},
+		{"fora do índice mantém o erro original", ErrUpstreamUnavailable, "01310100", ErrUpstreamUnavailable, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			primary := &scriptedProvider{err: tt.primaryErr}
+			got, err := Fallback(primary, index).Lookup(context.Background(), tt.cep)
+			if !errors.Is(err, tt.expectErr) {
+				t.Fatalf("Lookup() erro = %v; expect %v", err, tt.expectErr)
+			}
+			switch tt.expectFrom {
+			case "primary":
+				if got.Logradouro != "" {
+					t.Errorf("Lookup() = %+v; expect resposta do primary", got)
+				}
+			case "offline":
+				if got.Logradouro != "Praça da Sé" {
+					t.Errorf("Lookup() = %+v; expect resposta do índice", got)
+				}
+			}
+		})
+	}
+}
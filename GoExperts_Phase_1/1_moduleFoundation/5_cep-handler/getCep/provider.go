@@ -0,0 +1,290 @@
+package getCep
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"GoProject/1_moduleFoundation/2_HTTPClient/httpClient"
+)
+
+// Provider representa uma fonte de endereços (ViaCEP, BrasilAPI, ...).
+// Toda implementação devolve o endereço no formato da struct ViaCEP.
+type Provider interface {
+	Name() string
+	Lookup(ctx context.Context, cep string) (*ViaCEP, error)
+}
+
+// DefaultProvider é usado por GetCepFunc: consulta ViaCEP e BrasilAPI em paralelo.
+var DefaultProvider Provider = NewResolver(NewViaCEPProvider(), NewBrasilAPIProvider())
+
+// ViaCEPURLEnv é a variável de ambiente que troca o endereço da ViaCEP usado
+// por DefaultProvider e DefaultSearcher. Ex.: VIACEP_BASE_URL=http://localhost:8081/ws/
+// para usar o cmd/fakeviacep sem acesso à internet.
+const ViaCEPURLEnv = "VIACEP_BASE_URL"
+
+func init() {
+	if baseURL := os.Getenv(ViaCEPURLEnv); baseURL != "" {
+		UseViaCEP(baseURL)
+	}
+}
+
+// UseViaCEP faz DefaultProvider e DefaultSearcher consultarem só a ViaCEP no
+// endereço informado (ex.: o cmd/fakeviacep). A BrasilAPI sai do DefaultProvider
+// para que nenhuma busca dependa da internet.
+// Deve ser chamado antes de montar cache/breaker em volta do DefaultProvider.
+func UseViaCEP(baseURL string) {
+	viaCEP := &ViaCEPProvider{BaseURL: baseURL}
+	DefaultProvider = viaCEP
+	DefaultSearcher = viaCEP
+}
+
+// ############################## VIACEP #######################################
+
+// ViaCEPProvider consulta https://viacep.com.br/ws/<cep>/json/
+type ViaCEPProvider struct {
+	BaseURL string       // Ex.: "https://viacep.com.br/ws/" (httptest nos testes)
+	Client  *http.Client // nil ==> defaultClient (timeout de 5s)
+	Retry   RetryPolicy  // Zero value ==> DefaultRetryPolicy
+}
+
+// viaCEPResponse inclui o campo "erro", que a ViaCEP devolve com status 200
+// quando o CEP tem formato válido mas não existe: {"erro": true} ou {"erro": "true"}
+type viaCEPResponse struct {
+	ViaCEP
+	Erro any `json:"erro"`
+}
+
+// NewViaCEPProvider cria um ViaCEPProvider apontando para a API pública
+func NewViaCEPProvider() *ViaCEPProvider {
+	return &ViaCEPProvider{BaseURL: "https://viacep.com.br/ws/"}
+}
+
+func (p *ViaCEPProvider) Name() string { return "viacep" }
+
+func (p *ViaCEPProvider) Lookup(ctx context.Context, cep string) (*ViaCEP, error) {
+	var data viaCEPResponse
+	if err := getJSON(ctx, p.Client, p.Retry, joinURL(p.BaseURL, cep, "json/"), &data); err != nil {
+		return nil, err
+	}
+	if data.Erro == true || data.Erro == "true" {
+		return nil, ErrCEPNotFound
+	}
+	if data.Cep == "" {
+		return nil, fmt.Errorf("%w: viacep devolveu resposta sem CEP", ErrUpstreamUnavailable)
+	}
+	return &data.ViaCEP, nil
+}
+
+// ############################## BRASILAPI ####################################
+
+// BrasilAPIProvider consulta https://brasilapi.com.br/api/cep/v1/<cep>
+type BrasilAPIProvider struct {
+	BaseURL string
+	Client  *http.Client
+	Retry   RetryPolicy
+}
+
+// NewBrasilAPIProvider cria um BrasilAPIProvider apontando para a API pública
+func NewBrasilAPIProvider() *BrasilAPIProvider {
+	return &BrasilAPIProvider{BaseURL: "https://brasilapi.com.br/api/cep/v1/"}
+}
+
+// brasilAPIResponse é o formato devolvido pela BrasilAPI (campos em inglês)
+type brasilAPIResponse struct {
+	Cep          string `json:"cep"`
+	State        string `json:"state"`
+	City         string `json:"city"`
+	Neighborhood string `json:"neighborhood"`
+	Street       string `json:"street"`
+}
+
+func (p *BrasilAPIProvider) Name() string { return "brasilapi" }
+
+func (p *BrasilAPIProvider) Lookup(ctx context.Context, cep string) (*ViaCEP, error) {
+	var data brasilAPIResponse
+	// A BrasilAPI responde 404 para CEP inexistente e 400 para formato inválido
+	if err := getJSON(ctx, p.Client, p.Retry, joinURL(p.BaseURL, cep), &data); err != nil {
+		return nil, err
+	}
+	if data.Cep == "" {
+		return nil, fmt.Errorf("%w: brasilapi devolveu resposta sem CEP", ErrUpstreamUnavailable)
+	}
+
+	// Mapeia para a struct ViaCEP (a BrasilAPI devolve o CEP sem máscara)
+	formatted, err := Format(data.Cep)
+	if err != nil {
+		formatted = data.Cep
+	}
+	return &ViaCEP{
+		Cep:        formatted,
+		Logradouro: data.Street,
+		Bairro:     data.Neighborhood,
+		Localidade: data.City,
+		Uf:         data.State,
+	}, nil
+}
+
+// ############################## RESOLVER #####################################
+
+// Resolver consulta vários providers ao mesmo tempo e devolve a primeira
+// resposta válida, cancelando as requisições que ainda estão em andamento.
+type Resolver struct {
+	Providers []Provider
+}
+
+// NewResolver cria um Resolver com os providers informados
+func NewResolver(providers ...Provider) *Resolver {
+	return &Resolver{Providers: providers}
+}
+
+func (r *Resolver) Name() string { return "resolver" }
+
+func (r *Resolver) Lookup(ctx context.Context, cep string) (*ViaCEP, error) {
+	if len(r.Providers) == 0 {
+		return nil, errors.New("resolver: nenhum provider configurado")
+	}
+
+	// Cancela os providers perdedores assim que houver um vencedor
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	type result struct {
+		name string
+		addr *ViaCEP
+		err  error
+	}
+
+	// Canal com buffer: as goroutines perdedoras não ficam bloqueadas
+	results := make(chan result, len(r.Providers))
+	for _, p := range r.Providers {
+		go func(p Provider) {
+			addr, err := p.Lookup(ctx, cep)
+			results <- result{name: p.Name(), addr: addr, err: err}
+		}(p)
+	}
+
+	var errs []error
+	for range r.Providers {
+		select {
+		case res := <-results:
+			if res.err == nil {
+				return res.addr, nil
+			}
+			errs = append(errs, fmt.Errorf("%s: %w", res.name, res.err))
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+	return nil, combineErrors(errs)
+}
+
+// combineErrors reduz os erros dos providers a um único erro sentinela.
+// Prioridade: CEP inválido > CEP não encontrado > serviço indisponível.
+func combineErrors(errs []error) error {
+	detail := errors.Join(errs...)
+	for _, sentinel := range []error{ErrInvalidCEP, ErrCEPNotFound} {
+		for _, err := range errs {
+			if errors.Is(err, sentinel) {
+				return fmt.Errorf("%w: %v", sentinel, detail)
+			}
+		}
+	}
+	return fmt.Errorf("%w: %v", ErrUpstreamUnavailable, detail)
+}
+
+// ############################## HTTP #########################################
+
+// HTTPMetrics conta as tentativas feitas pelo client padrão dos providers
+var HTTPMetrics = &httpClient.Metrics{}
+
+// HedgeStats conta as cópias disparadas pelo client padrão (ver UseHedging)
+var HedgeStats = &httpClient.HedgeStats{}
+
+// defaultClient espera até 5 segundos pelos headers de cada tentativa e 10 no
+// total, com as novas tentativas (http.DefaultClient não tem timeout)
+var defaultClient = newDefaultClient(httpClient.HedgePolicy{})
+
+func newDefaultClient(hedge httpClient.HedgePolicy) *http.Client {
+	return httpClient.New(httpClient.Options{
+		Timeout:               10 * time.Second,
+		ResponseHeaderTimeout: 5 * time.Second,
+		UserAgent:             "GoProject-getCep/1.0",
+		Hedge:                 hedge,
+		Middlewares:           []httpClient.Middleware{httpClient.Measure(HTTPMetrics)},
+	})
+}
+
+// UseHedging faz o client padrão disparar uma cópia da busca que não responde
+// em delay (ex.: a latência p95 da ViaCEP), limitada a budget cópias por busca.
+// delay <= 0 desliga. Deve ser chamado ao subir, antes das buscas.
+func UseHedging(delay time.Duration, budget float64) {
+	defaultClient = newDefaultClient(httpClient.HedgePolicy{Delay: delay, Budget: budget, Stats: HedgeStats})
+}
+
+// getJSON executa um GET com o context informado e faz o parse do corpo em dst.
+// As falhas transitórias (rede, 429 e 5xx) são repetidas pelo httpClient.Retry
+// conforme a RetryPolicy; aqui o resultado final vira um dos erros sentinela
+// (ErrInvalidCEP, ErrCEPNotFound, ErrUpstreamUnavailable).
+func getJSON(ctx context.Context, client *http.Client, policy RetryPolicy, url string, dst any) error {
+	if client == nil {
+		client = defaultClient
+	}
+	// Cópia rasa: a política é do provider, o client pode ser compartilhado
+	retrying := *client
+	retrying.Transport = httpClient.Chain(client.Transport, httpClient.Retry(retryPolicyOrDefault(policy)))
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidCEP, err) // O CEP gerou uma URL inválida
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := retrying.Do(req)
+	if err != nil {
+		// Cancelamento/timeout do chamador não é falha do upstream: devolve como veio
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		return fmt.Errorf("%w: %v", ErrUpstreamUnavailable, err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		return fmt.Errorf("%w: %v", ErrUpstreamUnavailable, err)
+	}
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusBadRequest:
+		return ErrInvalidCEP
+	case http.StatusNotFound:
+		return ErrCEPNotFound
+	default: // 429 e 5xx chegam aqui depois das novas tentativas
+		return fmt.Errorf("%w: status %s", ErrUpstreamUnavailable, resp.Status)
+	}
+
+	if err := json.Unmarshal(body, dst); err != nil { // Parse para o formato da struct
+		return fmt.Errorf("%w: resposta inválida: %v", ErrUpstreamUnavailable, err)
+	}
+	return nil
+}
+
+// joinURL monta base + partes separadas por "/" (ex.: ".../ws/" + "01001000" + "json/")
+func joinURL(base string, parts ...string) string {
+	url := strings.TrimSuffix(base, "/")
+	for _, part := range parts {
+		url += "/" + part
+	}
+	return url
+}
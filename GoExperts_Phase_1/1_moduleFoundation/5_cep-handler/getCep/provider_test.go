@@ -0,0 +1,263 @@
+package getCep
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// Rodar os testes:  go test ./1_moduleFoundation/5_cep-handler/getCep -v
+
+const viaCEPBody = `{"cep":"01001-000","logradouro":"Praça da Sé","bairro":"Sé","localidade":"São Paulo","uf":"SP","ibge":"3550308","ddd":"11"}`
+const brasilAPIBody = `{"cep":"01001000","state":"SP","city":"São Paulo","neighborhood":"Sé","street":"Praça da Sé","service":"viacep"}`
+
+// fakeServer sobe um httptest.Server que responde body após delay (ou aborta se o client cancelar)
+func fakeServer(t *testing.T, status int, body string, delay time.Duration, canceled chan<- struct{}) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-time.After(delay):
+		case <-r.Context().Done():
+			if canceled != nil {
+				canceled <- struct{}{}
+			}
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		w.Write([]byte(body))
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestViaCEPProvider(t *testing.T) {
+	var gotPath string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.Path
+		w.Write([]byte(viaCEPBody))
+	}))
+	defer srv.Close()
+
+	p := &ViaCEPProvider{BaseURL: srv.URL + "/ws/"}
+	addr, err := p.Lookup(context.Background(), "01001000")
+	if err != nil {
+		t.Fatalf("Lookup() erro inesperado: %v", err)
+	}
+	if gotPath != "/ws/01001000/json/" {
+		t.Errorf("path = %q; expect /ws/01001000/json/", gotPath)
+	}
+	if addr.Logradouro != "Praça da Sé" || addr.Ibge != "3550308" {
+		t.Errorf("endereço inesperado: %+v", addr)
+	}
+}
+
+func TestBrasilAPIProvider(t *testing.T) {
+	srv := fakeServer(t, http.StatusOK, brasilAPIBody, 0, nil)
+
+	p := &BrasilAPIProvider{BaseURL: srv.URL}
+	addr, err := p.Lookup(context.Background(), "01001000")
+	if err != nil {
+		t.Fatalf("Lookup() erro inesperado: %v", err)
+	}
+
+	expect := ViaCEP{Cep: "01001-000", Logradouro: "Praça da Sé", Bairro: "Sé", Localidade: "São Paulo", Uf: "SP"}
+	if *addr != expect {
+		t.Errorf("Lookup() = %+v; expect %+v", *addr, expect)
+	}
+}
+
+func TestResolver(t *testing.T) {
+	t.Run("primeira resposta vence e as demais são canceladas", func(t *testing.T) {
+		canceled := make(chan struct{}, 1)
+		slow := fakeServer(t, http.StatusOK, viaCEPBody, 5*time.Second, canceled)
+		fast := fakeServer(t, http.StatusOK, brasilAPIBody, 0, nil)
+
+		r := NewResolver(&ViaCEPProvider{BaseURL: slow.URL}, &BrasilAPIProvider{BaseURL: fast.URL})
+		addr, err := r.Lookup(context.Background(), "01001000")
+		if err != nil {
+			t.Fatalf("Lookup() erro inesperado: %v", err)
+		}
+		if addr.Cep != "01001-000" {
+			t.Errorf("Cep = %q; expect 01001-000", addr.Cep)
+		}
+
+		select {
+		case <-canceled:
+		case <-time.After(2 * time.Second):
+			t.Fatal("o provider lento não foi cancelado")
+		}
+	})
+
+	t.Run("falha de um provider usa o outro", func(t *testing.T) {
+		down := fakeServer(t, http.StatusInternalServerError, "", 0, nil)
+		up := fakeServer(t, http.StatusOK, viaCEPBody, 50*time.Millisecond, nil)
+
+		r := NewResolver(&BrasilAPIProvider{BaseURL: down.URL, Retry: fastRetry}, &ViaCEPProvider{BaseURL: up.URL})
+		addr, err := r.Lookup(context.Background(), "01001000")
+		if err != nil {
+			t.Fatalf("Lookup() erro inesperado: %v", err)
+		}
+		if addr.Localidade != "São Paulo" {
+			t.Errorf("Localidade = %q; expect São Paulo", addr.Localidade)
+		}
+	})
+
+	t.Run("todos falham", func(t *testing.T) {
+		a := fakeServer(t, http.StatusInternalServerError, "", 0, nil)
+		b := fakeServer(t, http.StatusOK, `{"erro": true}`, 0, nil)
+
+		r := NewResolver(&BrasilAPIProvider{BaseURL: a.URL, Retry: fastRetry}, &ViaCEPProvider{BaseURL: b.URL})
+		if _, err := r.Lookup(context.Background(), "99999999"); err == nil {
+			t.Fatal("Lookup() esperava erro quando todos os providers falham")
+		}
+	})
+}
+
+// fastRetry evita esperas longas nos testes
+var fastRetry = RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond, MaxDelay: 5 * time.Millisecond}
+
+func TestViaCEPProviderErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		status int
+		body   string
+		expect error
+	}{
+		{"erro booleano", http.StatusOK, `{"erro": true}`, ErrCEPNotFound},
+		{"erro string", http.StatusOK, `{"erro": "true"}`, ErrCEPNotFound},
+		{"formato inválido", http.StatusBadRequest, `<html>Bad Request</html>`, ErrInvalidCEP},
+		{"5xx persistente", http.StatusServiceUnavailable, ``, ErrUpstreamUnavailable},
+		{"json inválido", http.StatusOK, `{`, ErrUpstreamUnavailable},
+	}
+
+	for _, item := range tests {
+		t.Run(item.name, func(t *testing.T) {
+			srv := fakeServer(t, item.status, item.body, 0, nil)
+			p := &ViaCEPProvider{BaseURL: srv.URL, Retry: fastRetry}
+
+			_, err := p.Lookup(context.Background(), "01001000")
+			if !errors.Is(err, item.expect) {
+				t.Errorf("Lookup() erro = %v; expect %v", err, item.expect)
+			}
+		})
+	}
+}
+
+func TestGetJSONRetry(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) < 3 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		w.Write([]byte(viaCEPBody))
+	}))
+	defer srv.Close()
+
+	p := &ViaCEPProvider{BaseURL: srv.URL, Retry: fastRetry}
+	if _, err := p.Lookup(context.Background(), "01001000"); err != nil {
+		t.Fatalf("Lookup() erro inesperado: %v", err)
+	}
+	if got := calls.Load(); got != 3 {
+		t.Errorf("chamadas = %d; expect 3", got)
+	}
+}
+
+func TestGetJSONNoRetryOnNotFound(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer srv.Close()
+
+	p := &BrasilAPIProvider{BaseURL: srv.URL, Retry: fastRetry}
+	if _, err := p.Lookup(context.Background(), "99999999"); !errors.Is(err, ErrCEPNotFound) {
+		t.Fatalf("Lookup() erro = %v; expect %v", err, ErrCEPNotFound)
+	}
+	if got := calls.Load(); got != 1 {
+		t.Errorf("chamadas = %d; expect 1 (404 não deve ser repetido)", got)
+	}
+}
+
+func TestGetCepContextCanceled(t *testing.T) {
+	srv := fakeServer(t, http.StatusOK, viaCEPBody, 5*time.Second, nil)
+	p := &ViaCEPProvider{BaseURL: srv.URL, Retry: fastRetry}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	_, err := p.Lookup(ctx, "01001000")
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Lookup() erro = %v; expect %v", err, context.DeadlineExceeded)
+	}
+}
+
+func TestResolverCombineErrors(t *testing.T) {
+	down := fakeServer(t, http.StatusInternalServerError, "", 0, nil)
+	missing := fakeServer(t, http.StatusOK, `{"erro": true}`, 0, nil)
+
+	r := NewResolver(&BrasilAPIProvider{BaseURL: down.URL, Retry: fastRetry}, &ViaCEPProvider{BaseURL: missing.URL})
+	_, err := r.Lookup(context.Background(), "99999999")
+	if !errors.Is(err, ErrCEPNotFound) || errors.Is(err, ErrUpstreamUnavailable) {
+		t.Fatalf("Lookup() erro = %v; expect apenas %v", err, ErrCEPNotFound)
+	}
+}
+
+func TestUseViaCEP(t *testing.T) {
+	previousProvider, previousSearcher := DefaultProvider, DefaultSearcher
+	t.Cleanup(func() { DefaultProvider, DefaultSearcher = previousProvider, previousSearcher })
+
+	srv := fakeServer(t, http.StatusOK, viaCEPBody, 0, nil)
+	UseViaCEP(srv.URL + "/ws/")
+
+	addr, err := GetCepFunc("01001000")
+	if err != nil {
+		t.Fatalf("GetCepFunc() erro inesperado: %v", err)
+	}
+	if addr.Logradouro != "Praça da Sé" {
+		t.Errorf("GetCepFunc().Logradouro = %q; expect Praça da Sé", addr.Logradouro)
+	}
+	if p, ok := DefaultSearcher.(*ViaCEPProvider); !ok || p.BaseURL != srv.URL+"/ws/" {
+		t.Errorf("DefaultSearcher = %#v; expect ViaCEPProvider em %s/ws/", DefaultSearcher, srv.URL)
+	}
+}
+
+func TestUseHedging(t *testing.T) {
+	previous := defaultClient
+	t.Cleanup(func() { defaultClient = previous })
+
+	// 1ª chamada presa até ser cancelada; a cópia responde na hora
+	var calls atomic.Int32
+	canceled := make(chan struct{}, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) == 1 {
+			<-r.Context().Done()
+			canceled <- struct{}{}
+			return
+		}
+		w.Write([]byte(viaCEPBody))
+	}))
+	defer srv.Close()
+
+	UseHedging(20*time.Millisecond, 0.1)
+	before := HedgeStats.Snapshot()
+
+	p := &ViaCEPProvider{BaseURL: srv.URL, Retry: RetryPolicy{MaxAttempts: 1}}
+	if _, err := p.Lookup(context.Background(), "01001000"); err != nil {
+		t.Fatalf("Lookup() erro inesperado: %v", err)
+	}
+	if after := HedgeStats.Snapshot(); after.Wins != before.Wins+1 {
+		t.Errorf("HedgeStats.Wins = %d; expect %d", after.Wins, before.Wins+1)
+	}
+	select {
+	case <-canceled:
+	case <-time.After(time.Second):
+		t.Error("a chamada lenta não foi cancelada")
+	}
+}
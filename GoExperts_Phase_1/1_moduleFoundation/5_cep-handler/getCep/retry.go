@@ -0,0 +1,26 @@
+package getCep
+
+import (
+	"time"
+
+	"GoProject/1_moduleFoundation/2_HTTPClient/httpClient"
+)
+
+// RetryPolicy define quantas tentativas fazer e quanto esperar entre elas.
+// Só erros transitórios (rede, 429 e 5xx) são repetidos.
+type RetryPolicy = httpClient.RetryPolicy
+
+// DefaultRetryPolicy é usada quando o provider não define a sua
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts: 3,
+	BaseDelay:   200 * time.Millisecond,
+	MaxDelay:    2 * time.Second,
+}
+
+// retryPolicyOrDefault devolve DefaultRetryPolicy quando a política não foi configurada
+func retryPolicyOrDefault(p RetryPolicy) RetryPolicy {
+	if p.MaxAttempts <= 0 {
+		return DefaultRetryPolicy
+	}
+	return p
+}
@@ -0,0 +1,109 @@
+package getCep
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"unicode/utf8"
+)
+
+// Searcher busca CEPs a partir do endereço (caminho inverso do Provider)
+type Searcher interface {
+	Search(ctx context.Context, uf, cidade, logradouro string) ([]ViaCEP, error)
+}
+
+// DefaultSearcher é usado por Search. Só a ViaCEP oferece busca por endereço.
+var DefaultSearcher Searcher = NewViaCEPProvider()
+
+// Tamanho mínimo exigido pela ViaCEP para cidade e logradouro
+const minSearchLen = 3
+
+// ufs são as siglas válidas (26 estados + DF)
+var ufs = map[string]bool{
+	"AC": true, "AL": true, "AP": true, "AM": true, "BA": true, "CE": true, "DF": true,
+	"ES": true, "GO": true, "MA": true, "MT": true, "MS": true, "MG": true, "PA": true,
+	"PB": true, "PR": true, "PE": true, "PI": true, "RJ": true, "RN": true, "RS": true,
+	"RO": true, "RR": true, "SC": true, "SP": true, "SE": true, "TO": true,
+}
+
+// Search valida os parâmetros e busca os CEPs de um logradouro com o DefaultSearcher.
+// Ex.: Search(ctx, "SP", "São Paulo", "Paulista"). Nenhum resultado não é erro.
+func Search(ctx context.Context, uf, cidade, logradouro string) ([]ViaCEP, error) {
+	uf, cidade, logradouro, err := normalizeSearch(uf, cidade, logradouro)
+	if err != nil {
+		return nil, err
+	}
+	return DefaultSearcher.Search(ctx, uf, cidade, logradouro)
+}
+
+// normalizeSearch aplica as regras da ViaCEP: UF válida, cidade e logradouro com 3+ caracteres
+func normalizeSearch(uf, cidade, logradouro string) (string, string, string, error) {
+	uf = strings.ToUpper(strings.TrimSpace(uf))
+	cidade = strings.TrimSpace(cidade)
+	logradouro = strings.TrimSpace(logradouro)
+
+	if !ufs[uf] {
+		return "", "", "", fmt.Errorf("%w: uf %q desconhecida", ErrInvalidSearch, uf)
+	}
+	if utf8.RuneCountInString(cidade) < minSearchLen {
+		return "", "", "", fmt.Errorf("%w: cidade deve ter pelo menos %d caracteres", ErrInvalidSearch, minSearchLen)
+	}
+	if utf8.RuneCountInString(logradouro) < minSearchLen {
+		return "", "", "", fmt.Errorf("%w: logradouro deve ter pelo menos %d caracteres", ErrInvalidSearch, minSearchLen)
+	}
+	return uf, cidade, logradouro, nil
+}
+
+// Search consulta https://viacep.com.br/ws/<UF>/<cidade>/<logradouro>/json/
+func (p *ViaCEPProvider) Search(ctx context.Context, uf, cidade, logradouro string) ([]ViaCEP, error) {
+	endpoint := joinURL(p.BaseURL, url.PathEscape(uf), url.PathEscape(cidade), url.PathEscape(logradouro), "json/")
+
+	var results []ViaCEP
+	if err := getJSON(ctx, p.Client, p.Retry, endpoint, &results); err != nil {
+		// Na busca, um 400 da ViaCEP significa parâmetros inválidos (não CEP inválido)
+		if errors.Is(err, ErrInvalidCEP) {
+			return nil, fmt.Errorf("%w: rejeitada pela viacep", ErrInvalidSearch)
+		}
+		return nil, err
+	}
+	if results == nil {
+		results = []ViaCEP{}
+	}
+	return results, nil
+}
+
+// ############################## PAGINAÇÃO ####################################
+
+// SearchPage é uma página do resultado da busca
+type SearchPage struct {
+	Items      []ViaCEP `json:"items"`
+	Page       int      `json:"page"`
+	PageSize   int      `json:"page_size"`
+	Total      int      `json:"total"`
+	TotalPages int      `json:"total_pages"`
+}
+
+// Paginate recorta os resultados na página informada (começando em 1).
+// Página além do fim devolve Items vazio, mas mantém Total e TotalPages.
+func Paginate(results []ViaCEP, page, pageSize int) SearchPage {
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 {
+		pageSize = 1
+	}
+
+	total := len(results)
+	start := min((page-1)*pageSize, total)
+	end := min(start+pageSize, total)
+
+	return SearchPage{
+		Items:      append([]ViaCEP{}, results[start:end]...),
+		Page:       page,
+		PageSize:   pageSize,
+		Total:      total,
+		TotalPages: (total + pageSize - 1) / pageSize,
+	}
+}
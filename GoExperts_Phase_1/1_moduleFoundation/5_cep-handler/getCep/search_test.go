@@ -0,0 +1,113 @@
+package getCep
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestViaCEPProviderSearch(t *testing.T) {
+	var gotPath string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.EscapedPath()
+		w.Write([]byte(`[{"cep":"01310-100","logradouro":"Avenida Paulista","localidade":"São Paulo","uf":"SP"},
+			{"cep":"01310-200","logradouro":"Avenida Paulista","localidade":"São Paulo","uf":"SP"}]`))
+	}))
+	defer srv.Close()
+
+	useDefaultSearcher(t, &ViaCEPProvider{BaseURL: srv.URL + "/ws/"})
+
+	results, err := Search(context.Background(), "sp", " São Paulo ", "Avenida Paulista")
+	if err != nil {
+		t.Fatalf("Search() erro inesperado: %v", err)
+	}
+	if expect := "/ws/SP/S%C3%A3o%20Paulo/Avenida%20Paulista/json/"; gotPath != expect {
+		t.Errorf("path = %q; expect %q", gotPath, expect)
+	}
+	if len(results) != 2 || results[1].Cep != "01310-200" {
+		t.Errorf("Search() = %+v; expect 2 resultados", results)
+	}
+}
+
+func TestSearchEmpty(t *testing.T) {
+	srv := fakeServer(t, http.StatusOK, `[]`, 0, nil)
+	useDefaultSearcher(t, &ViaCEPProvider{BaseURL: srv.URL})
+
+	results, err := Search(context.Background(), "SP", "São Paulo", "Rua Que Não Existe")
+	if err != nil || results == nil || len(results) != 0 {
+		t.Errorf("Search() = %v, %v; expect lista vazia sem erro", results, err)
+	}
+}
+
+func TestSearchValidation(t *testing.T) {
+	// Nenhum destes casos pode chegar ao servidor
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("requisição inesperada: %s", r.URL)
+	}))
+	defer srv.Close()
+	useDefaultSearcher(t, &ViaCEPProvider{BaseURL: srv.URL})
+
+	tests := []struct {
+		name                   string
+		uf, cidade, logradouro string
+	}{
+		{"uf vazia", "", "São Paulo", "Paulista"},
+		{"uf inexistente", "XX", "São Paulo", "Paulista"},
+		{"cidade curta", "SP", "Sã", "Paulista"},
+		{"logradouro curto", "SP", "São Paulo", "Av"},
+		{"logradouro só espaços", "SP", "São Paulo", "     "},
+	}
+
+	for _, item := range tests {
+		t.Run(item.name, func(t *testing.T) {
+			_, err := Search(context.Background(), item.uf, item.cidade, item.logradouro)
+			if !errors.Is(err, ErrInvalidSearch) {
+				t.Errorf("Search() erro = %v; expect %v", err, ErrInvalidSearch)
+			}
+		})
+	}
+}
+
+func TestPaginate(t *testing.T) {
+	results := make([]ViaCEP, 23)
+	for i := range results {
+		results[i].Cep = string(rune('A' + i))
+	}
+
+	tests := []struct {
+		name           string
+		page, pageSize int
+		expectLen      int
+		expectFirst    string
+		expectPages    int
+	}{
+		{"primeira página", 1, 10, 10, "A", 3},
+		{"última página incompleta", 3, 10, 3, "U", 3},
+		{"página além do fim", 4, 10, 0, "", 3},
+		{"página inválida vira 1", 0, 5, 5, "A", 5},
+		{"tudo em uma página", 1, 50, 23, "A", 1},
+	}
+
+	for _, item := range tests {
+		t.Run(item.name, func(t *testing.T) {
+			page := Paginate(results, item.page, item.pageSize)
+			if len(page.Items) != item.expectLen || page.Total != 23 || page.TotalPages != item.expectPages {
+				t.Errorf("Paginate(%d, %d) = %d itens, total %d, %d páginas; expect %d itens, total 23, %d páginas",
+					item.page, item.pageSize, len(page.Items), page.Total, page.TotalPages, item.expectLen, item.expectPages)
+			}
+			if item.expectLen > 0 && page.Items[0].Cep != item.expectFirst {
+				t.Errorf("primeiro item = %q; expect %q", page.Items[0].Cep, item.expectFirst)
+			}
+		})
+	}
+}
+
+// useDefaultSearcher troca o DefaultSearcher durante o teste
+func useDefaultSearcher(t *testing.T, s Searcher) {
+	t.Helper()
+	previous := DefaultSearcher
+	DefaultSearcher = s
+	t.Cleanup(func() { DefaultSearcher = previous })
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"GoProject/1_moduleFoundation/5_cep-handler/cepGrpc"
+	"GoProject/1_moduleFoundation/5_cep-handler/cepStore"
+	"GoProject/1_moduleFoundation/5_cep-handler/getCep"
+	"GoProject/1_moduleFoundation/5_cep-handler/pb"
+	"context"
+	"log"
+	"net"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/reflection"
+)
+
+// Para testar com o grpcurl (a reflection está ligada):
+// grpcurl -plaintext -d '{"cep": "01001000"}' localhost:50051 cep.v1.AddressService/GetAddress
+// grpcurl -plaintext -d '{"ceps": ["01001000", "14093070"]}' localhost:50051 cep.v1.AddressService/GetAddresses
+
+// serveGRPC sobe o AddressService em addr, com as mesmas camadas do HTTP
+// (cache, breaker, histórico) porque usa o mesmo getCep.DefaultProvider
+func serveGRPC(addr string) (*grpc.Server, error) {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+
+	srv := cepGrpc.NewServer()
+	srv.Timeout = lookupTimeout
+	srv.Concurrency = batchWorkers
+	srv.MaxBatch = maxBatchSize
+	srv.Stale = func(cep string) (*getCep.ViaCEP, bool) { return staleAddress(cep) }
+
+	server := grpc.NewServer(
+		grpc.UnaryInterceptor(func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+			return handler(cepStore.WithClient(ctx, grpcClientID(ctx)), req)
+		}),
+		grpc.StreamInterceptor(func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+			return handler(srv, &clientStream{ServerStream: ss, ctx: cepStore.WithClient(ss.Context(), grpcClientID(ss.Context()))})
+		}),
+	)
+	pb.RegisterAddressServiceServer(server, srv)
+	reflection.Register(server)
+
+	go func() {
+		log.Printf("Servidor gRPC ouvindo em %s", addr)
+		if err := server.Serve(listener); err != nil {
+			log.Printf("Erro no servidor gRPC: %v", err)
+		}
+	}()
+	return server, nil
+}
+
+// grpcClientID identifica quem fez a busca para o histórico (como o clientID do HTTP):
+// metadata x-client-id ou o IP de origem
+func grpcClientID(ctx context.Context) string {
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if ids := md.Get("x-client-id"); len(ids) > 0 && ids[0] != "" {
+			return ids[0]
+		}
+	}
+	if p, ok := peer.FromContext(ctx); ok {
+		if host, _, err := net.SplitHostPort(p.Addr.String()); err == nil {
+			return host
+		}
+		return p.Addr.String()
+	}
+	return ""
+}
+
+// clientStream troca o ctx do stream para levar o client ID
+type clientStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *clientStream) Context() context.Context { return s.ctx }
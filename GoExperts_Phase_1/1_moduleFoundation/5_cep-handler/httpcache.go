@@ -0,0 +1,91 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Cache HTTP do GET /?cep=: o endereço de um CEP quase nunca muda, então o
+// navegador e os proxies podem guardar a resposta e revalidar com If-None-Match.
+// O ETag é o hash do corpo: não depende de qual cache (ou nenhum) está na frente
+// do upstream, e muda sozinho se a ViaCEP corrigir o endereço.
+
+// cacheMaxAge é o Cache-Control max-age das respostas com endereço
+const cacheMaxAge = 24 * time.Hour
+
+// maxValidators limita os CEPs com Last-Modified guardado na memória
+const maxValidators = 100000
+
+// etagOf devolve o ETag forte do corpo da resposta
+func etagOf(body []byte) string {
+	sum := sha256.Sum256(body)
+	return `"` + hex.EncodeToString(sum[:16]) + `"`
+}
+
+// validators lembra desde quando cada CEP responde com o mesmo ETag, para o
+// Last-Modified. É só memória do processo: ao reiniciar, vale a hora da 1ª resposta.
+type validators struct {
+	mu   sync.Mutex
+	seen map[string]validator // cep ==> último ETag e desde quando
+}
+
+type validator struct {
+	etag     string
+	modified time.Time
+}
+
+var lastModified = &validators{seen: make(map[string]validator)}
+
+// since devolve o Last-Modified do CEP: a hora em que o ETag atual apareceu
+func (v *validators) since(cep, etag string, now time.Time) time.Time {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	if seen, ok := v.seen[cep]; ok && seen.etag == etag {
+		return seen.modified
+	}
+	if len(v.seen) >= maxValidators {
+		clear(v.seen) // Simples e raro: os CEPs voltam com a hora atual
+	}
+	modified := now.UTC().Truncate(time.Second) // O header só tem segundos
+	v.seen[cep] = validator{etag: etag, modified: modified}
+	return modified
+}
+
+// notModified diz se o cliente já tem a versão atual. If-None-Match tem
+// prioridade; If-Modified-Since só vale sem ele (RFC 9110, seção 13.2.2).
+func notModified(r *http.Request, etag string, modified time.Time) bool {
+	if inm := r.Header.Get("If-None-Match"); inm != "" {
+		for _, candidate := range strings.Split(inm, ",") {
+			candidate = strings.TrimSpace(candidate)
+			// Comparação fraca: W/"abc" vale como "abc" no GET
+			if candidate == "*" || strings.TrimPrefix(candidate, "W/") == etag {
+				return true
+			}
+		}
+		return false
+	}
+
+	if ims := r.Header.Get("If-Modified-Since"); ims != "" {
+		t, err := http.ParseTime(ims)
+		return err == nil && !modified.After(t)
+	}
+	return false
+}
+
+// setCacheHeaders escreve ETag, Last-Modified e Cache-Control. Resposta stale
+// (upstream fora) não deve ser guardada: o cliente revalida na próxima vez.
+func setCacheHeaders(w http.ResponseWriter, etag string, modified time.Time, stale bool) {
+	w.Header().Set("ETag", etag)
+	w.Header().Set("Last-Modified", modified.Format(http.TimeFormat))
+	if stale {
+		w.Header().Set("Cache-Control", "no-cache")
+		return
+	}
+	w.Header().Set("Cache-Control", "public, max-age="+strconv.Itoa(int(cacheMaxAge.Seconds())))
+}
@@ -0,0 +1,100 @@
+package label
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Code128 é o código de barras usado nas etiquetas dos Correios e transportadoras.
+// Cada símbolo tem 3 barras e 3 espaços, com larguras de 1 a 4 módulos (11 no total).
+// Sequências de dígitos de tamanho par usam o conjunto C (2 dígitos por símbolo),
+// o resto usa o conjunto B (ASCII 32 a 127).
+
+// code128Patterns são as larguras (barra, espaço, barra, ...) de cada valor.
+// 103/104/105 são os inícios A/B/C e 106 é o stop (com a barra final).
+var code128Patterns = [107]string{
+	"212222", "222122", "222221", "121223", "121322", "131222", "122213", "122312", "132212", "221213",
+	"221312", "231212", "112232", "122132", "122231", "113222", "123122", "123221", "223211", "221132",
+	"221231", "213212", "223112", "312131", "311222", "321122", "321221", "312212", "322112", "322211",
+	"212123", "212321", "232121", "111323", "131123", "131321", "112313", "132113", "132311", "211313",
+	"231113", "231311", "112133", "112331", "132131", "113123", "113321", "133121", "313121", "211331",
+	"231131", "213113", "213311", "213131", "311123", "311321", "331121", "312113", "312311", "332111",
+	"314111", "221411", "431111", "111224", "111422", "121124", "121421", "141122", "141221", "112214",
+	"112412", "122114", "122411", "142112", "142211", "241211", "221114", "413111", "241112", "134111",
+	"111242", "121142", "121241", "114212", "124112", "124211", "411212", "421112", "421211", "212141",
+	"214121", "412121", "111143", "111341", "131141", "114113", "114311", "411113", "411311", "113141",
+	"114131", "311141", "411131", "211412", "211214", "211232", "2331112",
+}
+
+const (
+	code128StartB = 104
+	code128StartC = 105
+	code128Stop   = 106
+)
+
+var errBarcodeText = errors.New("código de barras: texto vazio ou com caractere fora do ASCII")
+
+// code128Values converte o texto nos valores do Code128, com início e dígito verificador
+func code128Values(text string) ([]int, error) {
+	if text == "" {
+		return nil, errBarcodeText
+	}
+
+	var values []int
+	if len(text)%2 == 0 && isDigits(text) {
+		values = append(values, code128StartC)
+		for i := 0; i < len(text); i += 2 {
+			values = append(values, int(text[i]-'0')*10+int(text[i+1]-'0'))
+		}
+	} else {
+		values = append(values, code128StartB)
+		for _, c := range text {
+			if c < 32 || c > 127 {
+				return nil, fmt.Errorf("%w: %q", errBarcodeText, c)
+			}
+			values = append(values, int(c)-32)
+		}
+	}
+
+	// Dígito verificador: início + soma de valor * posição, módulo 103
+	sum := values[0]
+	for i, v := range values[1:] {
+		sum += v * (i + 1)
+	}
+	return append(values, sum%103, code128Stop), nil
+}
+
+// Bar é uma barra preta; X e Width em módulos (a barra mais fina tem largura 1)
+type Bar struct {
+	X, Width int
+}
+
+// Code128 devolve as barras do código e a largura total em módulos (sem a zona de silêncio)
+func Code128(text string) ([]Bar, int, error) {
+	values, err := code128Values(text)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var bars []Bar
+	x := 0
+	for _, v := range values {
+		for i, w := range code128Patterns[v] {
+			width := int(w - '0')
+			if i%2 == 0 { // Posições pares são barras; ímpares, espaços
+				bars = append(bars, Bar{X: x, Width: width})
+			}
+			x += width
+		}
+	}
+	return bars, x, nil
+}
+
+func isDigits(s string) bool {
+	for _, c := range s {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,73 @@
+package label
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestCode128Patterns(t *testing.T) {
+	seen := map[string]bool{}
+	for v, pattern := range code128Patterns {
+		expect := 11
+		if v == code128Stop {
+			expect = 13
+		}
+		sum := 0
+		for _, w := range pattern {
+			sum += int(w - '0')
+		}
+		if sum != expect {
+			t.Errorf("valor %d (%s) soma %d módulos; expect %d", v, pattern, sum, expect)
+		}
+		if seen[pattern] {
+			t.Errorf("valor %d (%s) repetido", v, pattern)
+		}
+		seen[pattern] = true
+	}
+}
+
+func TestCode128Values(t *testing.T) {
+	tests := []struct {
+		text   string
+		expect []int
+		err    error
+	}{
+		// Conjunto C: 105 + 1*1 + 0*2 + 10*3 + 0*4 = 136; 136 % 103 = 33
+		{"01001000", []int{105, 1, 0, 10, 0, 33, 106}, nil},
+		// Conjunto B (tamanho ímpar): 104 + 17*1 + 18*2 + 19*3 = 214; 214 % 103 = 8
+		{"123", []int{104, 17, 18, 19, 8, 106}, nil},
+		{"", nil, errBarcodeText},
+		{"São", nil, errBarcodeText},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			values, err := code128Values(tt.text)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("code128Values(%q) erro = %v; expect %v", tt.text, err, tt.err)
+			}
+			if !slices.Equal(values, tt.expect) {
+				t.Errorf("code128Values(%q) = %v; expect %v", tt.text, values, tt.expect)
+			}
+		})
+	}
+}
+
+func TestCode128Bars(t *testing.T) {
+	bars, width, err := Code128("01001000")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Início + 4 pares + verificador = 6 símbolos de 11 módulos, mais o stop de 13
+	if width != 6*11+13 {
+		t.Errorf("largura = %d; expect %d", width, 6*11+13)
+	}
+	// 3 barras por símbolo e 4 no stop
+	if len(bars) != 6*3+4 {
+		t.Errorf("%d barras; expect %d", len(bars), 6*3+4)
+	}
+	if last := bars[len(bars)-1]; last.X+last.Width != width {
+		t.Errorf("última barra termina em %d; expect %d", last.X+last.Width, width)
+	}
+}
@@ -0,0 +1,129 @@
+package label
+
+import (
+	"GoProject/1_moduleFoundation/5_cep-handler/getCep"
+	"embed"
+	"errors"
+	"fmt"
+	htmlTemplate "html/template"
+	"io"
+	"strings"
+	"text/template"
+)
+
+// Etiqueta de envio 4x6 polegadas (100x150mm): remetente em cima, destinatário
+// em destaque e o CEP do destinatário em Code128 para a triagem.
+// ZPL é a linguagem das impressoras térmicas Zebra; o HTML é para imprimir do navegador.
+
+//go:embed templates/label.zpl templates/label.html
+var files embed.FS
+
+var ErrInvalidLabel = errors.New("etiqueta inválida")
+
+// Party é o remetente ou o destinatário. Address vem da ViaCEP; Number e
+// Complement são do cliente (o complemento da ViaCEP é a faixa do CEP, ex.: "lado ímpar").
+type Party struct {
+	Name       string
+	Number     string
+	Complement string
+	Address    *getCep.ViaCEP
+}
+
+// Label é uma etiqueta de envio
+type Label struct {
+	From Party
+	To   Party
+}
+
+// Validate exige nome, número e endereço do remetente e do destinatário
+func (l Label) Validate() error {
+	for _, p := range []struct {
+		role  string
+		party Party
+	}{{"remetente", l.From}, {"destinatário", l.To}} {
+		switch {
+		case strings.TrimSpace(p.party.Name) == "":
+			return fmt.Errorf("%w: nome do %s vazio", ErrInvalidLabel, p.role)
+		case strings.TrimSpace(p.party.Number) == "":
+			return fmt.Errorf("%w: número do %s vazio (use \"s/n\" se não houver)", ErrInvalidLabel, p.role)
+		case p.party.Address == nil:
+			return fmt.Errorf("%w: endereço do %s vazio", ErrInvalidLabel, p.role)
+		}
+		if _, err := getCep.Normalize(p.party.Address.Cep); err != nil {
+			return fmt.Errorf("%w: cep do %s: %v", ErrInvalidLabel, p.role, err)
+		}
+	}
+	return nil
+}
+
+// lines são as linhas de texto de um endereço, iguais no ZPL e no HTML
+type lines struct {
+	Name   string
+	Street string // "Praça da Sé, 100 - Sala 2"
+	City   string // "Sé - São Paulo/SP"
+	Cep    string // "01001-000"
+}
+
+func linesOf(p Party) lines {
+	a := p.Address
+	street := strings.TrimSpace(a.Logradouro + ", " + p.Number)
+	if a.Logradouro == "" { // CEP geral de cidade pequena: não tem logradouro
+		street = p.Number
+	}
+	if p.Complement != "" {
+		street += " - " + p.Complement
+	}
+
+	city := a.Localidade + "/" + a.Uf
+	if a.Bairro != "" {
+		city = a.Bairro + " - " + city
+	}
+	return lines{Name: p.Name, Street: street, City: city, Cep: a.Cep}
+}
+
+// data é o que os templates recebem
+type data struct {
+	From, To lines
+	Barcode  string // CEP do destinatário só com dígitos
+	Bars     []Bar
+	Width    int // Largura do código de barras em módulos, com 10 de silêncio de cada lado
+}
+
+func newData(l Label) (data, error) {
+	if err := l.Validate(); err != nil {
+		return data{}, err
+	}
+	cep, _ := getCep.Normalize(l.To.Address.Cep)
+	bars, modules, err := Code128(cep)
+	if err != nil {
+		return data{}, err
+	}
+	return data{From: linesOf(l.From), To: linesOf(l.To), Barcode: cep, Bars: bars, Width: modules + 20}, nil
+}
+
+var zplLabel = template.Must(template.New("label.zpl").
+	Funcs(template.FuncMap{"zpl": zplEscape}).
+	ParseFS(files, "templates/label.zpl"))
+
+var htmlLabel = htmlTemplate.Must(htmlTemplate.New("label.html").ParseFS(files, "templates/label.html"))
+
+// ZPL escreve a etiqueta em ZPL (203 dpi). O código de barras é gerado pela impressora (^BC).
+func ZPL(w io.Writer, l Label) error {
+	d, err := newData(l)
+	if err != nil {
+		return err
+	}
+	return zplLabel.Execute(w, d)
+}
+
+// HTML escreve a etiqueta como página para imprimir, com o código de barras em SVG
+func HTML(w io.Writer, l Label) error {
+	d, err := newData(l)
+	if err != nil {
+		return err
+	}
+	return htmlLabel.Execute(w, d)
+}
+
+// zplEscape troca os caracteres de comando do ZPL pelo hexa do ^FH (ex.: ^ ==> \5E)
+var zplEscape = strings.NewReplacer(`\`, `\5C`, "^", `\5E`, "~", `\7E`).Replace
@@ -0,0 +1,97 @@
+package label
+
+import (
+	"GoProject/1_moduleFoundation/5_cep-handler/getCep"
+	"bytes"
+	"errors"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// go test ./label -update  ==> regrava os arquivos em testdata/ com a saída atual
+var update = flag.Bool("update", false, "regrava os arquivos golden")
+
+func testLabel() Label {
+	return Label{
+		From: Party{
+			Name:   "Loja Exemplo ^Ltda~",
+			Number: "1578",
+			Address: &getCep.ViaCEP{
+				Cep: "01310-200", Logradouro: "Avenida Paulista", Bairro: "Bela Vista", Localidade: "São Paulo", Uf: "SP",
+			},
+		},
+		To: Party{
+			Name:       "Maria <Souza> & Filhos",
+			Number:     "100",
+			Complement: "Apto 12",
+			Address: &getCep.ViaCEP{
+				Cep: "14093-070", Logradouro: "Rua Capitão Adelmio Norberto da Silva", Bairro: "Ribeirânia",
+				Localidade: "Ribeirão Preto", Uf: "SP",
+			},
+		},
+	}
+}
+
+// golden compara got com testdata/name (ou regrava com -update)
+func golden(t *testing.T, name string, got []byte) {
+	t.Helper()
+	path := filepath.Join("testdata", name)
+	if *update {
+		if err := os.WriteFile(path, got, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	expect, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("%v (rode com -update para criar)", err)
+	}
+	if !bytes.Equal(got, expect) {
+		t.Errorf("saída diferente de %s (rode com -update se a mudança for intencional):\n%s", path, got)
+	}
+}
+
+func TestZPL(t *testing.T) {
+	var buf bytes.Buffer
+	if err := ZPL(&buf, testLabel()); err != nil {
+		t.Fatal(err)
+	}
+	golden(t, "label.zpl", buf.Bytes())
+}
+
+func TestHTML(t *testing.T) {
+	var buf bytes.Buffer
+	if err := HTML(&buf, testLabel()); err != nil {
+		t.Fatal(err)
+	}
+	golden(t, "label.html", buf.Bytes())
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name   string
+		change func(l *Label)
+		err    error
+	}{
+		{"completa", func(l *Label) {}, nil},
+		{"sem logradouro", func(l *Label) { l.To.Address.Logradouro = "" }, nil},
+		{"sem nome", func(l *Label) { l.To.Name = " " }, ErrInvalidLabel},
+		{"sem número", func(l *Label) { l.From.Number = "" }, ErrInvalidLabel},
+		{"sem endereço", func(l *Label) { l.To.Address = nil }, ErrInvalidLabel},
+		{"cep inválido", func(l *Label) { l.From.Address.Cep = "abc" }, ErrInvalidLabel},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l := testLabel()
+			tt.change(&l)
+			if err := l.Validate(); !errors.Is(err, tt.err) {
+				t.Errorf("Validate() = %v; expect %v", err, tt.err)
+			}
+			if err := ZPL(&bytes.Buffer{}, l); !errors.Is(err, tt.err) {
+				t.Errorf("ZPL() = %v; expect %v", err, tt.err)
+			}
+		})
+	}
+}
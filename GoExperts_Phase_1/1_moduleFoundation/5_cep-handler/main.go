@@ -1,15 +1,264 @@
 package main
 
 import (
+	"GoProject/1_moduleFoundation/5_cep-handler/address"
+	"GoProject/1_moduleFoundation/5_cep-handler/cepGraphql"
+	"GoProject/1_moduleFoundation/5_cep-handler/cepStore"
 	"GoProject/1_moduleFoundation/5_cep-handler/getCep"
+	"GoProject/1_moduleFoundation/5_cep-handler/label"
+	"GoProject/1_moduleFoundation/5_cep-handler/municipios"
+	"GoProject/1_moduleFoundation/6_serverMux/rateLimit"
+	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"math"
+	"net"
 	"net/http"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"google.golang.org/grpc"
+)
+
+// lookupTimeout limita o tempo total de uma busca (todas as tentativas e providers)
+const lookupTimeout = 10 * time.Second
+
+// maxBatchSize limita quantos CEPs cabem em um POST /ceps
+const maxBatchSize = 1000
+
+// Paginação do GET /search
+const (
+	defaultPageSize = 10
+	maxPageSize     = 50
+	maxPage         = 10000
+)
+
+// Histórico do GET /ceps/{cep}/history
+const (
+	defaultHistoryLimit = 50
+	maxHistoryLimit     = 500
 )
 
+// cache fica na frente do getCep.DefaultProvider (nil se não configurado)
+var cache *getCep.Cache
+
+// breaker protege o upstream (ViaCEP/BrasilAPI) quando ele começa a falhar
+var breaker *getCep.Breaker
+
+// store grava endereços e histórico no MySQL (nil sem -dsn)
+var store *cepStore.Store
 
+// batchWorkers limita as buscas simultâneas de um POST /ceps
+var batchWorkers = 10
+
+// go run . -cache-file=cache.json
+// go run . -dsn="myuser:root@tcp(localhost:3306)/goexpert?parseTime=True"  ==> grava endereços e histórico no MySQL
+// go run . -offline-index=ceps.idx           ==> usa o índice local quando o upstream falha
+// go run . -offline-index=ceps.idx -offline  ==> responde só pelo índice local (sem internet)
+// go run . -viacep-url=http://localhost:8081/ws/ ==> usa o cmd/fakeviacep no lugar da ViaCEP
 func main() {
-	http.HandleFunc("/", BuscaCepHandler)
-	http.ListenAndServe(":8080", nil) // Sobe servidor http
+	addr := flag.String("addr", ":8080", "endereço do servidor http")
+	cacheSize := flag.Int("cache-size", 10000, "máximo de CEPs no cache")
+	cacheTTL := flag.Duration("cache-ttl", 24*time.Hour, "validade de um endereço no cache")
+	negativeTTL := flag.Duration("cache-negative-ttl", 10*time.Minute, "validade de um CEP não encontrado no cache")
+	cacheFile := flag.String("cache-file", "", "snapshot JSON do cache: carregado ao subir e salvo ao desligar")
+	flag.IntVar(&batchWorkers, "batch-workers", batchWorkers, "buscas simultâneas por POST /ceps")
+	dsn := flag.String("dsn", "", "MySQL para gravar endereços e histórico, ex.: o do docker-compose (vazio desabilita)")
+	breakerThreshold := flag.Int("breaker-threshold", 5, "falhas seguidas do upstream para abrir o circuito")
+	breakerCoolDown := flag.Duration("breaker-cooldown", 30*time.Second, "tempo com o circuito aberto antes de testar o upstream de novo")
+	offlineIndex := flag.String("offline-index", "", "índice local gerado por cmd/importcep (fallback quando o upstream falha)")
+	offline := flag.Bool("offline", false, "responde só pelo -offline-index, sem chamar ViaCEP/BrasilAPI")
+	grpcAddr := flag.String("grpc-addr", ":50051", "endereço do servidor gRPC (vazio desabilita)")
+	rateLimitRPM := flag.Int("rate-limit", 600, "requisições por minuto por IP (0 desabilita); POST /ceps e /graphql têm 1/10 disso")
+	rateBurst := flag.Int("rate-burst", 50, "rajada máxima por IP antes de o -rate-limit valer")
+	apiKeys := flag.String("api-keys", "", "chaves X-API-Key aceitas, separadas por vírgula: cada uma tem o próprio balde do -rate-limit")
+	hedgeDelay := flag.Duration("hedge-delay", 400*time.Millisecond, "espera pela ViaCEP/BrasilAPI antes de disparar uma cópia da busca (0 desabilita)")
+	hedgeBudget := flag.Float64("hedge-budget", 0.1, "cópias por busca no longo prazo (0.1 = até 10% de requisições extras)")
+	viaCEPURL := flag.String("viacep-url", "", "usa só a ViaCEP neste endereço, ex.: o cmd/fakeviacep (padrão: $"+getCep.ViaCEPURLEnv+" ou a API pública)")
+	flag.Parse()
+
+	getCep.UseHedging(*hedgeDelay, *hedgeBudget)
+
+	if *viaCEPURL != "" {
+		getCep.UseViaCEP(*viaCEPURL)
+	}
+
+	// Ordem das camadas: histórico ==> cache ==> circuit breaker / índice offline ==> ViaCEP/BrasilAPI
+	upstream, err := buildUpstream(*offlineIndex, *offline, getCep.BreakerOptions{
+		FailureThreshold: *breakerThreshold,
+		CoolDown:         *breakerCoolDown,
+	})
+	if err != nil {
+		log.Fatalf("Erro ao configurar o modo offline: %v", err)
+	}
+	cache = getCep.NewCache(upstream, getCep.CacheOptions{
+		MaxEntries:  *cacheSize,
+		TTL:         *cacheTTL,
+		NegativeTTL: *negativeTTL,
+	})
+	if *cacheFile != "" {
+		if err := cache.LoadFile(*cacheFile); err != nil {
+			log.Printf("Erro ao carregar cache de %s: %v", *cacheFile, err)
+		}
+	}
+	getCep.DefaultProvider = cache
+
+	var recording *cepStore.RecordingProvider
+	if *dsn != "" {
+		db, err := cepStore.Open(*dsn)
+		if err != nil {
+			log.Fatalf("Erro ao abrir conexão com o MySQL de -dsn: %v", err)
+		}
+		store = cepStore.NewStore(db)
+		if err := store.Migrate(); err != nil {
+			log.Fatalf("Erro ao migrar tabelas: %v", err)
+		}
+		// Acima do cache: buscas respondidas pelo cache também entram no histórico.
+		// A gravação é assíncrona, então o cache continua respondendo sem esperar o banco.
+		recording = store.Recording(getCep.DefaultProvider)
+		getCep.DefaultProvider = recording
+	}
+
+	var grpcServer *grpc.Server
+	if *grpcAddr != "" {
+		if grpcServer, err = serveGRPC(*grpcAddr); err != nil {
+			log.Fatalf("Erro ao subir servidor gRPC: %v", err)
+		}
+	}
+
+	server := &http.Server{Addr: *addr, Handler: newLimiter(*rateLimitRPM, *rateBurst, strings.Split(*apiKeys, ",")).Handler(routes())}
+	go func() {
+		log.Printf("Servidor ouvindo em %s", *addr)
+		if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Fatalf("Erro ao subir servidor: %v", err)
+		}
+	}()
+
+	// Aguarda Ctrl+C / SIGTERM para desligar com calma e salvar o cache
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	<-ctx.Done()
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		log.Printf("Erro ao desligar servidor: %v", err)
+	}
+	if grpcServer != nil {
+		grpcServer.GracefulStop()
+	}
+	if recording != nil {
+		if err := recording.Close(shutdownCtx); err != nil {
+			log.Printf("Erro ao gravar as últimas buscas: %v", err)
+		}
+	}
+
+	if *cacheFile != "" {
+		if err := cache.SaveFile(*cacheFile); err != nil {
+			log.Printf("Erro ao salvar cache em %s: %v", *cacheFile, err)
+		}
+	}
+}
+
+// buildUpstream monta as camadas abaixo do cache:
+//
+//	online:     circuit breaker ==> ViaCEP/BrasilAPI
+//	com índice: fallback(circuit breaker ==> ViaCEP/BrasilAPI, índice local)
+//	offline:    índice local
+func buildUpstream(indexPath string, offline bool, opts getCep.BreakerOptions) (getCep.Provider, error) {
+	if offline && indexPath == "" {
+		return nil, errors.New("-offline precisa de -offline-index")
+	}
+
+	var index *getCep.OfflineIndex
+	if indexPath != "" {
+		var err error
+		if index, err = getCep.LoadIndex(indexPath); err != nil {
+			return nil, err
+		}
+		log.Printf("Índice offline %s carregado: %d CEPs", indexPath, index.Len())
+	}
+	if offline {
+		return index, nil
+	}
+
+	breaker = getCep.NewBreaker(getCep.DefaultProvider, opts)
+	if index == nil {
+		return breaker, nil
+	}
+	return getCep.Fallback(breaker, index), nil
+}
+
+// newLimiter limita as requisições por cliente para ninguém gastar sozinho a
+// cota da ViaCEP. As rotas que buscam muitos CEPs de uma vez têm balde próprio e menor.
+// O cliente é a X-API-Key quando ela está em apiKeys (vários serviços atrás do
+// mesmo IP não dividem o balde) e o IP nos outros casos.
+func newLimiter(rpm, burst int, apiKeys []string) *rateLimit.Limiter {
+	if rpm <= 0 {
+		return rateLimit.New(rateLimit.Options{}) // Sem limite
+	}
+	valid := make(map[string]bool)
+	for _, key := range apiKeys {
+		if key = strings.TrimSpace(key); key != "" {
+			valid[key] = true
+		}
+	}
+	heavy := rateLimit.PerMinute(max(rpm/10, 1), max(burst/10, 1))
+	return rateLimit.New(rateLimit.Options{
+		Default: rateLimit.PerMinute(rpm, burst),
+		// Chave desconhecida conta pelo IP: inventar chaves não rende baldes novos
+		Key: func(r *http.Request) string {
+			if valid[r.Header.Get("X-API-Key")] {
+				return rateLimit.APIKeyOrIP(r)
+			}
+			return rateLimit.ClientIP(r)
+		},
+		Routes: map[string]rateLimit.Limit{
+			"POST /ceps":  heavy,
+			"/graphql":    heavy,
+			"GET /status": {}, // Monitoramento não entra no limite
+		},
+	})
+}
+
+// routes registra os endpoints do servidor
+func routes() *http.ServeMux {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", BuscaCepHandler)
+	mux.HandleFunc("POST /ceps", BatchCepHandler)
+	mux.HandleFunc("GET /ceps/{cep}/history", HistoryHandler)
+	mux.HandleFunc("GET /search", SearchHandler)
+	mux.HandleFunc("GET /cache/stats", CacheStatsHandler)
+	mux.HandleFunc("GET /status", StatusHandler)
+	mux.HandleFunc("GET /municipios", MunicipiosHandler)
+	mux.HandleFunc("GET /municipios/{codigo}", MunicipioHandler)
+	mux.HandleFunc("GET /municipios/uf/{uf}", MunicipiosUFHandler)
+	mux.HandleFunc("GET /labels", LabelHandler)
+	mux.HandleFunc("POST /addresses/verify", VerifyAddressHandler)
+
+	graphqlHandler, err := cepGraphql.NewHandler()
+	if err != nil {
+		log.Fatalf("Erro ao montar schema GraphQL: %v", err)
+	}
+	graphqlHandler.Timeout = lookupTimeout
+	graphqlHandler.Concurrency = batchWorkers
+	mux.Handle("/graphql", withClientID(graphqlHandler))
+	return mux
+}
+
+// withClientID guarda o clientID no ctx para o histórico (handlers que não chamam cepStore.WithClient)
+func withClientID(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		next.ServeHTTP(w, r.WithContext(cepStore.WithClient(r.Context(), clientID(r))))
+	})
 }
 
 func BuscaCepHandler(w http.ResponseWriter, r *http.Request) {
@@ -24,26 +273,393 @@ func BuscaCepHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	cep, error := getCep.GetCepFunc(cepParam) // usa a função modularizada
-	if error != nil {
+	// r.Context() é cancelado se o cliente desistir da requisição
+	ctx, cancel := context.WithTimeout(r.Context(), lookupTimeout)
+	defer cancel()
+	ctx = cepStore.WithClient(ctx, clientID(r))
+
+	cep, err := getCep.GetCep(ctx, cepParam) // usa a função modularizada
+	stale := false
+	if errors.Is(err, getCep.ErrCircuitOpen) {
+		// Upstream fora: melhor um endereço antigo do cache do que um erro
+		if old, ok := staleAddress(cepParam); ok {
+			w.Header().Set("Warning", `110 - "Response is Stale"`)
+			cep, err, stale = old, nil, true
+		}
+	}
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	// O ETag é o hash do corpo, então aqui o .Marshal (e não o Encoder direto)
+	result, err := json.Marshal(cep)
+	if err != nil {
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
+	result = append(result, '\n') // Mesmo corpo que o json.NewEncoder(w).Encode(cep) gerava
+
+	normalized, _ := getCep.Normalize(cepParam) // Já validado pelo GetCep
+	etag := etagOf(result)
+	modified := lastModified.since(normalized, etag, time.Now())
+	setCacheHeaders(w, etag, modified, stale)
+	if notModified(r, etag, modified) {
+		w.WriteHeader(http.StatusNotModified) // O cliente já tem este endereço
+		return
+	}
 
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
+	w.Write(result)
+}
+
+// batchItem é o resultado de um CEP no POST /ceps
+type batchItem struct {
+	Cep     string         `json:"cep"`
+	Status  int            `json:"status"`
+	Address *getCep.ViaCEP `json:"address,omitempty"`
+	Error   string         `json:"error,omitempty"`
+}
+
+// BatchCepHandler recebe um array JSON de CEPs (ex.: ["01001000", "14093-070"])
+// e devolve um resultado por CEP, na mesma ordem, cada um com seu status/erro.
+func BatchCepHandler(w http.ResponseWriter, r *http.Request) {
+	var ceps []string
+	r.Body = http.MaxBytesReader(w, r.Body, 1<<20) // 1MB
+	if err := json.NewDecoder(r.Body).Decode(&ceps); err != nil {
+		writeJSONError(w, http.StatusBadRequest, "o corpo deve ser um array JSON de CEPs")
+		return
+	}
+	if len(ceps) > maxBatchSize {
+		writeJSONError(w, http.StatusRequestEntityTooLarge, fmt.Sprintf("máximo de %d CEPs por requisição", maxBatchSize))
+		return
+	}
+
+	// r.Context() é cancelado se o cliente desconectar: o pool para de buscar
+	ctx, cancel := context.WithTimeout(r.Context(), lookupTimeout)
+	defer cancel()
+	ctx = cepStore.WithClient(ctx, clientID(r))
+
+	results := getCep.LookupBatch(ctx, ceps, batchWorkers)
+	if r.Context().Err() != nil {
+		log.Println("Request cancelada pelo cliente")
+		return
+	}
+
+	items := make([]batchItem, len(results))
+	for i, res := range results {
+		items[i] = batchItem{Cep: res.Cep, Status: http.StatusOK, Address: res.Address}
+		if res.Err != nil {
+			items[i].Status = statusFromError(res.Err)
+			items[i].Error = res.Err.Error()
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(items)
+}
+
+// HistoryHandler lista as últimas buscas de um CEP: GET /ceps/01001000/history?limit=20
+func HistoryHandler(w http.ResponseWriter, r *http.Request) {
+	if store == nil {
+		writeJSONError(w, http.StatusServiceUnavailable, "histórico desabilitado (servidor sem banco)")
+		return
+	}
+
+	limit, err := intParam(r.URL.Query().Get("limit"), defaultHistoryLimit)
+	if err != nil || limit < 1 || limit > maxHistoryLimit {
+		writeJSONError(w, http.StatusBadRequest, fmt.Sprintf("limit deve ser um número entre 1 e %d", maxHistoryLimit))
+		return
+	}
+
+	history, err := store.History(r.Context(), r.PathValue("cep"), limit)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(history)
+}
+
+// clientID identifica quem fez a requisição: header X-Client-ID ou o IP
+func clientID(r *http.Request) string {
+	if id := r.Header.Get("X-Client-ID"); id != "" {
+		return id
+	}
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+// SearchHandler busca CEPs pelo endereço:
+// GET /search?uf=SP&cidade=São Paulo&logradouro=Paulista&page=1&page_size=10
+func SearchHandler(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	page, err := intParam(query.Get("page"), 1)
+	if err != nil || page < 1 || page > maxPage {
+		writeJSONError(w, http.StatusBadRequest, fmt.Sprintf("page deve ser um número entre 1 e %d", maxPage))
+		return
+	}
+	pageSize, err := intParam(query.Get("page_size"), defaultPageSize)
+	if err != nil || pageSize < 1 || pageSize > maxPageSize {
+		writeJSONError(w, http.StatusBadRequest, fmt.Sprintf("page_size deve ser um número entre 1 e %d", maxPageSize))
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), lookupTimeout)
+	defer cancel()
+
+	results, err := getCep.Search(ctx, query.Get("uf"), query.Get("cidade"), query.Get("logradouro"))
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(getCep.Paginate(results, page, pageSize))
+}
+
+// MunicipioHandler resolve um código IBGE (o campo ibge da ViaCEP): GET /municipios/3550308
+func MunicipioHandler(w http.ResponseWriter, r *http.Request) {
+	m, err := municipios.ByCode(r.PathValue("codigo"))
+	switch {
+	case errors.Is(err, municipios.ErrInvalidCode):
+		writeJSONError(w, http.StatusBadRequest, err.Error())
+		return
+	case errors.Is(err, municipios.ErrNotFound):
+		writeJSONError(w, http.StatusNotFound, err.Error())
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(m)
+}
+
+// MunicipiosUFHandler lista os municípios de um estado: GET /municipios/uf/SP
+func MunicipiosUFHandler(w http.ResponseWriter, r *http.Request) {
+	list, err := municipios.ByUF(r.PathValue("uf"))
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(list)
+}
+
+// MunicipiosHandler busca pelo nome, sem diferenciar acentos:
+// GET /municipios?nome=sao paulo (&uf=SP para desempatar nomes repetidos)
+func MunicipiosHandler(w http.ResponseWriter, r *http.Request) {
+	nome, uf := r.URL.Query().Get("nome"), r.URL.Query().Get("uf")
+	if strings.TrimSpace(nome) == "" {
+		writeJSONError(w, http.StatusBadRequest, "informe o parâmetro nome")
+		return
+	}
+
+	list := []municipios.Municipio{} // Nenhum resultado responde [] e não null
+	for _, m := range municipios.ByName(nome) {
+		if uf == "" || strings.EqualFold(m.UF, uf) {
+			list = append(list, m)
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(list)
+}
+
+// LabelHandler busca os CEPs do remetente (from_) e do destinatário (to_) e gera a etiqueta:
+// GET /labels?from_name=Loja&from_cep=01310200&from_number=1578&to_name=Maria&to_cep=14093070&to_number=100&to_complement=Apto 12
+// format=html (padrão, para imprimir do navegador) ou format=zpl (impressora Zebra)
+func LabelHandler(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	format := query.Get("format")
+	if format == "" {
+		format = "html"
+	}
+	if format != "html" && format != "zpl" {
+		writeJSONError(w, http.StatusBadRequest, "format deve ser html ou zpl")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), lookupTimeout)
+	defer cancel()
+	ctx = cepStore.WithClient(ctx, clientID(r))
+
+	var l label.Label
+	for _, p := range []struct {
+		prefix string
+		party  *label.Party
+	}{{"from_", &l.From}, {"to_", &l.To}} {
+		addr, err := getCep.GetCep(ctx, query.Get(p.prefix+"cep"))
+		if err != nil {
+			writeError(w, r, fmt.Errorf("%scep: %w", p.prefix, err))
+			return
+		}
+		*p.party = label.Party{
+			Name:       query.Get(p.prefix + "name"),
+			Number:     query.Get(p.prefix + "number"),
+			Complement: query.Get(p.prefix + "complement"),
+			Address:    addr,
+		}
+	}
+
+	// Gera em memória para responder 400 (e não uma etiqueta pela metade) se falhar
+	var buf bytes.Buffer
+	render, contentType := label.HTML, "text/html; charset=utf-8"
+	if format == "zpl" {
+		render, contentType = label.ZPL, "application/zpl; charset=utf-8"
+	}
+	if err := render(&buf, l); err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, label.ErrInvalidLabel) {
+			status = http.StatusBadRequest
+		}
+		writeJSONError(w, status, err.Error())
+		return
+	}
+
+	w.Header().Set("Content-Type", contentType)
+	buf.WriteTo(w)
+}
+
+// verifyRequest é o corpo do POST /addresses/verify: o CEP e o endereço como o usuário digitou
+type verifyRequest struct {
+	Cep string `json:"cep"`
+	address.Input
+}
+
+// verifyResponse diz se o endereço bate com o do CEP e mostra o endereço oficial
+type verifyResponse struct {
+	address.Result
+	Address *getCep.ViaCEP `json:"address"`
+}
+
+// VerifyAddressHandler compara o endereço digitado com o do CEP, ignorando
+// abreviações, acentos e caixa:
+// POST /addresses/verify {"cep": "01310-200", "logradouro": "av paulista", "localidade": "sao paulo", "uf": "SP"}
+func VerifyAddressHandler(w http.ResponseWriter, r *http.Request) {
+	var req verifyRequest
+	r.Body = http.MaxBytesReader(w, r.Body, 1<<16) // 64KB
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSONError(w, http.StatusBadRequest, "o corpo deve ser um JSON com cep e o endereço digitado")
+		return
+	}
+	if req.Logradouro == "" && req.Bairro == "" && req.Localidade == "" && req.Uf == "" {
+		writeJSONError(w, http.StatusBadRequest, "informe ao menos um campo do endereço (logradouro, bairro, localidade ou uf)")
+		return
+	}
 
-	// Forma comentada: Se quiser posse do valor salvo em uma var use o .Marshal
-	// result, err := json.Marshal(cep)
-	// if err != nil {
-	//     w.WriteHeader(http.StatusInternalServerError)
-	//     return
-	// }
-	// w.Write(result)
+	ctx, cancel := context.WithTimeout(r.Context(), lookupTimeout)
+	defer cancel()
+	ctx = cepStore.WithClient(ctx, clientID(r))
 
-	// Forma recomendada (direta):
-	json.NewEncoder(w).Encode(cep)
+	addr, err := getCep.GetCep(ctx, req.Cep)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
 
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(verifyResponse{Result: address.Compare(req.Input, addr), Address: addr})
+}
+
+// intParam converte um parâmetro da query; vazio devolve o valor padrão
+func intParam(value string, fallback int) (int, error) {
+	if value == "" {
+		return fallback, nil
+	}
+	return strconv.Atoi(value)
+}
+
+// staleAddress procura o CEP no cache, mesmo expirado
+func staleAddress(cepParam string) (*getCep.ViaCEP, bool) {
+	if cache == nil {
+		return nil, false
+	}
+	normalized, err := getCep.Normalize(cepParam)
+	if err != nil {
+		return nil, false
+	}
+	return cache.Stale(normalized)
+}
+
+// StatusHandler mostra o estado do circuit breaker e os contadores do cache
+func StatusHandler(w http.ResponseWriter, r *http.Request) {
+	status := map[string]any{}
+	if breaker != nil {
+		status["breaker"] = breaker.Status()
+	}
+	if cache != nil {
+		status["cache"] = cache.Stats()
+	}
+	status["upstream"] = getCep.HTTPMetrics.Snapshot()
+	status["hedge"] = getCep.HedgeStats.Snapshot()
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(status)
+}
+
+// CacheStatsHandler devolve os contadores do cache: {"hits":..,"misses":..,"evictions":..,"entries":..}
+func CacheStatsHandler(w http.ResponseWriter, r *http.Request) {
+	if cache == nil {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(cache.Stats())
+}
+
+// statusFromError converte os erros sentinela do getCep em status HTTP
+func statusFromError(err error) int {
+	switch {
+	case errors.Is(err, getCep.ErrInvalidCEP), errors.Is(err, getCep.ErrInvalidSearch):
+		return http.StatusBadRequest // 400
+	case errors.Is(err, getCep.ErrCEPNotFound):
+		return http.StatusNotFound // 404
+	case errors.Is(err, getCep.ErrCircuitOpen):
+		return http.StatusServiceUnavailable // 503
+	case errors.Is(err, getCep.ErrUpstreamUnavailable):
+		return http.StatusBadGateway // 502
+	case errors.Is(err, context.DeadlineExceeded):
+		return http.StatusGatewayTimeout // 504
+	default:
+		return http.StatusInternalServerError // 500
+	}
+}
+
+// writeError responde {"error": "..."} com o status correspondente ao erro
+func writeError(w http.ResponseWriter, r *http.Request, err error) {
+	// Cliente cancelou: não há para quem responder
+	if errors.Is(err, context.Canceled) && r.Context().Err() != nil {
+		log.Println("Request cancelada pelo cliente")
+		return
+	}
+
+	// Circuito aberto: avisa o cliente quando vale a pena tentar de novo
+	var open *getCep.CircuitOpenError
+	if errors.As(err, &open) {
+		seconds := int(math.Ceil(open.RetryAfter.Seconds()))
+		w.Header().Set("Retry-After", strconv.Itoa(max(seconds, 1)))
+	}
+
+	status := statusFromError(err)
+	if status >= 500 && status != http.StatusServiceUnavailable {
+		log.Printf("Erro ao buscar CEP: %v", err)
+	}
+	writeJSONError(w, status, err.Error())
+}
+
+// writeJSONError responde {"error": msg} com o status informado
+func writeJSONError(w http.ResponseWriter, status int, msg string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]string{"error": msg})
 }
 
 // Criei a função o package getCep com a função GetCepFunc e modularizei o arquivo
@@ -51,4 +667,4 @@ func BuscaCepHandler(w http.ResponseWriter, r *http.Request) {
 // Para teste use o ThunderClient
 // Para teste use o ThunderClient
 // Para teste use o ThunderClient
-// Para teste use o ThunderClient
\ No newline at end of file
+// Para teste use o ThunderClient
@@ -0,0 +1,540 @@
+package main
+
+import (
+	"GoProject/1_moduleFoundation/5_cep-handler/cepStore"
+	"GoProject/1_moduleFoundation/5_cep-handler/getCep"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// stubProvider devolve sempre o mesmo endereço/erro, sem acessar a rede
+type stubProvider struct {
+	addr *getCep.ViaCEP
+	err  error
+}
+
+func (s stubProvider) Name() string { return "stub" }
+
+func (s stubProvider) Lookup(ctx context.Context, cep string) (*getCep.ViaCEP, error) {
+	return s.addr, s.err
+}
+
+// useProvider troca o getCep.DefaultProvider durante o teste
+func useProvider(t *testing.T, p getCep.Provider) {
+	t.Helper()
+	previous := getCep.DefaultProvider
+	getCep.DefaultProvider = p
+	t.Cleanup(func() { getCep.DefaultProvider = previous })
+}
+
+func TestBuscaCepHandlerStatus(t *testing.T) {
+	tests := []struct {
+		name   string
+		target string
+		stub   stubProvider
+		expect int
+	}{
+		{"sucesso", "/?cep=01001000", stubProvider{addr: &getCep.ViaCEP{Cep: "01001-000"}}, http.StatusOK},
+		{"sem cep", "/", stubProvider{}, http.StatusBadRequest},
+		{"rota inexistente", "/outra?cep=01001000", stubProvider{}, http.StatusNotFound},
+		{"cep inválido (upstream)", "/?cep=01001000", stubProvider{err: getCep.ErrInvalidCEP}, http.StatusBadRequest},
+		{"cep inválido (local)", "/?cep=abc", stubProvider{addr: &getCep.ViaCEP{}}, http.StatusBadRequest},
+		{"cep não encontrado", "/?cep=01009999", stubProvider{err: fmt.Errorf("viacep: %w", getCep.ErrCEPNotFound)}, http.StatusNotFound},
+		{"upstream fora do ar", "/?cep=01001000", stubProvider{err: getCep.ErrUpstreamUnavailable}, http.StatusBadGateway},
+		{"timeout", "/?cep=01001000", stubProvider{err: context.DeadlineExceeded}, http.StatusGatewayTimeout},
+	}
+
+	for _, item := range tests {
+		t.Run(item.name, func(t *testing.T) {
+			useProvider(t, item.stub)
+
+			rec := httptest.NewRecorder()
+			BuscaCepHandler(rec, httptest.NewRequest(http.MethodGet, item.target, nil))
+
+			if rec.Code != item.expect {
+				t.Errorf("GET %s = %d; expect %d", item.target, rec.Code, item.expect)
+			}
+		})
+	}
+}
+
+func TestCacheStatsHandler(t *testing.T) {
+	previous := cache
+	cache = getCep.NewCache(stubProvider{addr: &getCep.ViaCEP{Cep: "01001-000"}}, getCep.CacheOptions{})
+	t.Cleanup(func() { cache = previous })
+	useProvider(t, cache)
+
+	mux := routes()
+	for range 2 {
+		mux.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/?cep=01001000", nil))
+	}
+
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/cache/stats", nil))
+
+	var stats getCep.CacheStats
+	if err := json.NewDecoder(rec.Body).Decode(&stats); err != nil {
+		t.Fatalf("resposta inválida: %v", err)
+	}
+	if stats.Hits != 1 || stats.Misses != 1 {
+		t.Errorf("stats = %+v; expect 1 hit e 1 miss", stats)
+	}
+}
+
+func TestBatchCepHandler(t *testing.T) {
+	useProvider(t, stubProvider{addr: &getCep.ViaCEP{Cep: "01001-000"}})
+
+	body := strings.NewReader(`["01001000", "abc", "01001-000"]`)
+	rec := httptest.NewRecorder()
+	routes().ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/ceps", body))
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("POST /ceps = %d; expect 200", rec.Code)
+	}
+	var items []batchItem
+	if err := json.NewDecoder(rec.Body).Decode(&items); err != nil {
+		t.Fatalf("resposta inválida: %v", err)
+	}
+
+	expect := []struct {
+		cep    string
+		status int
+	}{
+		{"01001000", http.StatusOK},
+		{"abc", http.StatusBadRequest},
+		{"01001-000", http.StatusOK},
+	}
+	if len(items) != len(expect) {
+		t.Fatalf("len(items) = %d; expect %d", len(items), len(expect))
+	}
+	for i, e := range expect {
+		if items[i].Cep != e.cep || items[i].Status != e.status {
+			t.Errorf("items[%d] = %+v; expect cep %s status %d", i, items[i], e.cep, e.status)
+		}
+	}
+	if items[1].Error == "" || items[0].Address == nil {
+		t.Errorf("items = %+v; expect erro no item inválido e endereço nos demais", items)
+	}
+}
+
+func TestBatchCepHandlerBadRequest(t *testing.T) {
+	tests := []struct {
+		name   string
+		body   string
+		expect int
+	}{
+		{"não é array", `{"cep": "01001000"}`, http.StatusBadRequest},
+		{"json quebrado", `["01001000"`, http.StatusBadRequest},
+		{"lote grande demais", `[` + strings.Repeat(`"01001000",`, maxBatchSize) + `"01001000"]`, http.StatusRequestEntityTooLarge},
+	}
+
+	for _, item := range tests {
+		t.Run(item.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			routes().ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/ceps", strings.NewReader(item.body)))
+			if rec.Code != item.expect {
+				t.Errorf("POST /ceps = %d; expect %d", rec.Code, item.expect)
+			}
+		})
+	}
+}
+
+// stubSearcher devolve n resultados para qualquer busca
+type stubSearcher int
+
+func (n stubSearcher) Search(ctx context.Context, uf, cidade, logradouro string) ([]getCep.ViaCEP, error) {
+	return make([]getCep.ViaCEP, n), nil
+}
+
+func TestSearchHandler(t *testing.T) {
+	previous := getCep.DefaultSearcher
+	getCep.DefaultSearcher = stubSearcher(25)
+	t.Cleanup(func() { getCep.DefaultSearcher = previous })
+
+	tests := []struct {
+		name       string
+		target     string
+		expect     int
+		expectLen  int
+		expectPage int
+	}{
+		{"padrão", "/search?uf=SP&cidade=Sao+Paulo&logradouro=Paulista", http.StatusOK, 10, 1},
+		{"última página", "/search?uf=SP&cidade=Sao+Paulo&logradouro=Paulista&page=3&page_size=10", http.StatusOK, 5, 3},
+		{"logradouro curto", "/search?uf=SP&cidade=Sao+Paulo&logradouro=Pa", http.StatusBadRequest, 0, 0},
+		{"page inválida", "/search?uf=SP&cidade=Sao+Paulo&logradouro=Paulista&page=x", http.StatusBadRequest, 0, 0},
+		{"page_size grande demais", "/search?uf=SP&cidade=Sao+Paulo&logradouro=Paulista&page_size=500", http.StatusBadRequest, 0, 0},
+	}
+
+	for _, item := range tests {
+		t.Run(item.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			routes().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, item.target, nil))
+
+			if rec.Code != item.expect {
+				t.Fatalf("GET %s = %d; expect %d", item.target, rec.Code, item.expect)
+			}
+			if item.expect != http.StatusOK {
+				return
+			}
+			var page getCep.SearchPage
+			json.NewDecoder(rec.Body).Decode(&page)
+			if len(page.Items) != item.expectLen || page.Page != item.expectPage || page.Total != 25 {
+				t.Errorf("página = %d itens, page %d, total %d; expect %d itens, page %d, total 25",
+					len(page.Items), page.Page, page.Total, item.expectLen, item.expectPage)
+			}
+		})
+	}
+}
+
+func TestHistoryHandler(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{Logger: logger.Discard})
+	if err != nil {
+		t.Fatalf("Erro ao abrir SQLite: %v", err)
+	}
+	sqlDB, _ := db.DB()
+	sqlDB.SetMaxOpenConns(1)
+	defer sqlDB.Close()
+
+	previous := store
+	store = cepStore.NewStore(db)
+	t.Cleanup(func() { store = previous })
+	if err := store.Migrate(); err != nil {
+		t.Fatalf("Erro ao migrar tabelas: %v", err)
+	}
+	recording := store.Recording(stubProvider{addr: &getCep.ViaCEP{Cep: "01001-000"}})
+	useProvider(t, recording)
+
+	mux := routes()
+	for _, client := range []string{"ana", "bia"} {
+		req := httptest.NewRequest(http.MethodGet, "/?cep=01001000", nil)
+		req.Header.Set("X-Client-ID", client)
+		mux.ServeHTTP(httptest.NewRecorder(), req)
+	}
+	recording.Close(context.Background()) // Espera a gravação assíncrona
+
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/ceps/01001-000/history", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("GET /ceps/01001-000/history = %d; expect 200", rec.Code)
+	}
+	var history []cepStore.Lookup
+	json.NewDecoder(rec.Body).Decode(&history)
+	if len(history) != 2 || history[0].Client != "bia" {
+		t.Errorf("histórico = %+v; expect 2 buscas, a mais recente de bia", history)
+	}
+
+	rec = httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/ceps/abc/history", nil))
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("GET /ceps/abc/history = %d; expect 400", rec.Code)
+	}
+}
+
+func TestBuscaCepHandlerCircuitOpen(t *testing.T) {
+	previousBreaker, previousCache := breaker, cache
+	t.Cleanup(func() { breaker, cache = previousBreaker, previousCache })
+
+	upstream := &switchProvider{}
+	breaker = getCep.NewBreaker(upstream, getCep.BreakerOptions{FailureThreshold: 1, CoolDown: time.Minute})
+	cache = getCep.NewCache(breaker, getCep.CacheOptions{TTL: time.Nanosecond})
+	useProvider(t, cache)
+	mux := routes()
+
+	// 1ª busca preenche o cache (que expira logo em seguida)
+	upstream.err = nil
+	mux.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/?cep=01001000", nil))
+
+	// Upstream cai e o circuito abre
+	upstream.err = getCep.ErrUpstreamUnavailable
+	mux.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/?cep=14093070", nil))
+
+	t.Run("sem cache responde 503 com Retry-After", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/?cep=14093070", nil))
+		if rec.Code != http.StatusServiceUnavailable {
+			t.Fatalf("status = %d; expect 503", rec.Code)
+		}
+		if rec.Header().Get("Retry-After") != "60" {
+			t.Errorf("Retry-After = %q; expect 60", rec.Header().Get("Retry-After"))
+		}
+	})
+
+	t.Run("com cache antigo responde 200 stale", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/?cep=01001-000", nil))
+		if rec.Code != http.StatusOK || rec.Header().Get("Warning") == "" {
+			t.Fatalf("status = %d, Warning = %q; expect 200 com Warning", rec.Code, rec.Header().Get("Warning"))
+		}
+		if rec.Header().Get("Cache-Control") != "no-cache" {
+			t.Errorf("Cache-Control = %q; expect no-cache na resposta stale", rec.Header().Get("Cache-Control"))
+		}
+	})
+
+	t.Run("status mostra o circuito aberto", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/status", nil))
+		var status struct {
+			Breaker getCep.BreakerStatus `json:"breaker"`
+		}
+		json.NewDecoder(rec.Body).Decode(&status)
+		if len(status.Breaker.Transitions) != 1 || status.Breaker.RetryAfter == "" {
+			t.Errorf("status = %+v; expect circuito aberto com 1 transição", status.Breaker)
+		}
+	})
+}
+
+// switchProvider devolve err se configurado, senão um endereço
+type switchProvider struct{ err error }
+
+func (p *switchProvider) Name() string { return "switch" }
+
+func (p *switchProvider) Lookup(ctx context.Context, cep string) (*getCep.ViaCEP, error) {
+	if p.err != nil {
+		return nil, p.err
+	}
+	return &getCep.ViaCEP{Cep: cep}, nil
+}
+
+func TestGraphQLRoute(t *testing.T) {
+	useProvider(t, stubProvider{addr: &getCep.ViaCEP{Cep: "01001-000", Logradouro: "Praça da Sé", Uf: "SP"}})
+
+	body := `{"query": "{ address(cep: \"01001000\") { logradouro } }"}`
+	rec := httptest.NewRecorder()
+	routes().ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/graphql", strings.NewReader(body)))
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("POST /graphql = %d; expect 200", rec.Code)
+	}
+	expect := `{"data":{"address":{"logradouro":"Praça da Sé"}}}`
+	if got := strings.TrimSpace(rec.Body.String()); got != expect {
+		t.Errorf("POST /graphql = %s; expect %s", got, expect)
+	}
+}
+
+func TestMunicipiosRoutes(t *testing.T) {
+	tests := []struct {
+		target string
+		status int
+		expect string // Trecho esperado no corpo
+	}{
+		{"/municipios/3550308", http.StatusOK, `"nome":"São Paulo"`},
+		{"/municipios/3550309", http.StatusNotFound, `"error"`},
+		{"/municipios/abc", http.StatusBadRequest, `"error"`},
+		{"/municipios/uf/df", http.StatusOK, `[{"codigo":"5300108","nome":"Brasília"`},
+		{"/municipios/uf/XX", http.StatusBadRequest, `"error"`},
+		{"/municipios?nome=ribeirao%20preto", http.StatusOK, `"codigo":"3543402"`},
+		{"/municipios?nome=ribeirao%20preto&uf=RJ", http.StatusOK, `[]`},
+		{"/municipios", http.StatusBadRequest, `"error"`},
+	}
+
+	mux := routes()
+	for _, tt := range tests {
+		t.Run(tt.target, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, tt.target, nil))
+
+			if rec.Code != tt.status {
+				t.Errorf("GET %s = %d; expect %d", tt.target, rec.Code, tt.status)
+			}
+			if !strings.Contains(rec.Body.String(), tt.expect) {
+				t.Errorf("GET %s = %s; expect conter %s", tt.target, rec.Body.String(), tt.expect)
+			}
+		})
+	}
+}
+
+func TestLabelHandler(t *testing.T) {
+	useProvider(t, stubProvider{addr: &getCep.ViaCEP{Cep: "01001-000", Logradouro: "Praça da Sé", Localidade: "São Paulo", Uf: "SP"}})
+	const params = "from_name=Loja&from_cep=01001000&from_number=1&to_name=Maria&to_cep=01001000&to_number=2"
+
+	tests := []struct {
+		name        string
+		target      string
+		status      int
+		contentType string
+		expect      string // Trecho esperado no corpo
+	}{
+		{"html", "/labels?" + params, http.StatusOK, "text/html; charset=utf-8", "<svg"},
+		{"zpl", "/labels?" + params + "&format=zpl", http.StatusOK, "application/zpl; charset=utf-8", "^FD>;01001000^FS"},
+		{"formato inválido", "/labels?" + params + "&format=pdf", http.StatusBadRequest, "application/json", "format"},
+		{"sem nome", "/labels?from_cep=01001000&from_number=1&to_cep=01001000&to_number=2", http.StatusBadRequest, "application/json", "nome"},
+		{"cep inválido", "/labels?" + strings.Replace(params, "to_cep=01001000", "to_cep=abc", 1), http.StatusBadRequest, "application/json", "to_cep"},
+	}
+
+	mux := routes()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, tt.target, nil))
+
+			if rec.Code != tt.status {
+				t.Errorf("GET %s = %d; expect %d (%s)", tt.target, rec.Code, tt.status, rec.Body.String())
+			}
+			if got := rec.Header().Get("Content-Type"); got != tt.contentType {
+				t.Errorf("Content-Type = %q; expect %q", got, tt.contentType)
+			}
+			if !strings.Contains(rec.Body.String(), tt.expect) {
+				t.Errorf("GET %s = %s; expect conter %s", tt.target, rec.Body.String(), tt.expect)
+			}
+		})
+	}
+}
+
+func TestVerifyAddressHandler(t *testing.T) {
+	useProvider(t, stubProvider{addr: &getCep.ViaCEP{Cep: "01310-200", Logradouro: "Avenida Paulista", Bairro: "Bela Vista", Localidade: "São Paulo", Uf: "SP"}})
+
+	tests := []struct {
+		name   string
+		body   string
+		status int
+		match  bool
+	}{
+		{"abreviado", `{"cep": "01310200", "logradouro": "Av. Paulista", "localidade": "sao paulo", "uf": "sp"}`, http.StatusOK, true},
+		{"outra rua", `{"cep": "01310200", "logradouro": "Rua Augusta", "localidade": "São Paulo"}`, http.StatusOK, false},
+		{"sem endereço", `{"cep": "01310200"}`, http.StatusBadRequest, false},
+		{"cep inválido", `{"cep": "abc", "logradouro": "Av. Paulista"}`, http.StatusBadRequest, false},
+		{"corpo inválido", `[`, http.StatusBadRequest, false},
+	}
+
+	mux := routes()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			mux.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/addresses/verify", strings.NewReader(tt.body)))
+
+			if rec.Code != tt.status {
+				t.Fatalf("POST /addresses/verify = %d; expect %d (%s)", rec.Code, tt.status, rec.Body.String())
+			}
+			if rec.Code != http.StatusOK {
+				return
+			}
+			var resp struct {
+				Match   bool           `json:"match"`
+				Address *getCep.ViaCEP `json:"address"`
+			}
+			json.Unmarshal(rec.Body.Bytes(), &resp)
+			if resp.Match != tt.match || resp.Address == nil {
+				t.Errorf("POST /addresses/verify = %s; expect match %v com o endereço", rec.Body.String(), tt.match)
+			}
+		})
+	}
+}
+
+func TestRateLimit(t *testing.T) {
+	useProvider(t, stubProvider{addr: &getCep.ViaCEP{Cep: "01001-000"}})
+	h := newLimiter(60, 2, []string{"parceiro"}).Handler(routes())
+
+	do := func(method, target string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(method, target, strings.NewReader(`["01001000"]`)))
+		return rec
+	}
+	withKey := func(key string) int {
+		req := httptest.NewRequest(http.MethodGet, "/?cep=01001000", nil)
+		req.Header.Set("X-API-Key", key)
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	for i := range 2 {
+		if rec := do(http.MethodGet, "/?cep=01001000"); rec.Code != http.StatusOK {
+			t.Fatalf("requisição %d = %d; expect 200", i+1, rec.Code)
+		}
+	}
+	rec := do(http.MethodGet, "/?cep=01001000")
+	if rec.Code != http.StatusTooManyRequests || rec.Header().Get("Retry-After") == "" {
+		t.Errorf("3ª requisição = %d (Retry-After %q); expect 429 com Retry-After", rec.Code, rec.Header().Get("Retry-After"))
+	}
+
+	// POST /ceps tem balde próprio (1 por rajada) e o /status não tem limite
+	if rec := do(http.MethodPost, "/ceps"); rec.Code != http.StatusOK {
+		t.Errorf("1º POST /ceps = %d; expect 200", rec.Code)
+	}
+	if rec := do(http.MethodPost, "/ceps"); rec.Code != http.StatusTooManyRequests {
+		t.Errorf("2º POST /ceps = %d; expect 429", rec.Code)
+	}
+	if rec := do(http.MethodGet, "/status"); rec.Code != http.StatusOK {
+		t.Errorf("GET /status = %d; expect 200 sem limite", rec.Code)
+	}
+
+	// Chave aceita tem balde próprio mesmo vindo do mesmo IP; chave inventada conta pelo IP
+	if code := withKey("parceiro"); code != http.StatusOK {
+		t.Errorf("GET com X-API-Key aceita = %d; expect 200", code)
+	}
+	if code := withKey("inventada"); code != http.StatusTooManyRequests {
+		t.Errorf("GET com X-API-Key desconhecida = %d; expect 429 (balde do IP)", code)
+	}
+}
+
+func TestBuscaCepHandlerConditionalGet(t *testing.T) {
+	upstream := &switchProvider{}
+	useProvider(t, upstream)
+
+	get := func(header, value string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/?cep=01001000", nil)
+		if header != "" {
+			req.Header.Set(header, value)
+		}
+		rec := httptest.NewRecorder()
+		BuscaCepHandler(rec, req)
+		return rec
+	}
+
+	first := get("", "")
+	etag, modified := first.Header().Get("ETag"), first.Header().Get("Last-Modified")
+	if first.Code != http.StatusOK || etag == "" || modified == "" {
+		t.Fatalf("1ª busca = %d, ETag %q, Last-Modified %q; expect 200 com os dois", first.Code, etag, modified)
+	}
+	if cc := first.Header().Get("Cache-Control"); cc != "public, max-age=86400" {
+		t.Errorf("Cache-Control = %q; expect public, max-age=86400", cc)
+	}
+
+	tests := []struct {
+		name   string
+		header string
+		value  string
+		status int
+	}{
+		{"mesmo etag", "If-None-Match", etag, http.StatusNotModified},
+		{"etag fraco", "If-None-Match", "W/" + etag, http.StatusNotModified},
+		{"lista de etags", "If-None-Match", `"outro", ` + etag, http.StatusNotModified},
+		{"qualquer", "If-None-Match", "*", http.StatusNotModified},
+		{"etag diferente", "If-None-Match", `"outro"`, http.StatusOK},
+		{"não modificado desde", "If-Modified-Since", modified, http.StatusNotModified},
+		{"modificado desde", "If-Modified-Since", "Mon, 01 Jan 2024 00:00:00 GMT", http.StatusOK},
+		{"data inválida", "If-Modified-Since", "ontem", http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := get(tt.header, tt.value)
+			if rec.Code != tt.status {
+				t.Fatalf("%s: %s = %d; expect %d", tt.header, tt.value, rec.Code, tt.status)
+			}
+			if rec.Header().Get("ETag") != etag {
+				t.Errorf("ETag = %q; expect %q", rec.Header().Get("ETag"), etag)
+			}
+			if tt.status == http.StatusNotModified && rec.Body.Len() != 0 {
+				t.Errorf("304 com corpo: %q", rec.Body.String())
+			}
+		})
+	}
+
+	// Endereço corrigido no upstream ==> ETag novo, e o ETag antigo não vale mais
+	useProvider(t, stubProvider{addr: &getCep.ViaCEP{Cep: "01001000", Logradouro: "Praça da Sé"}})
+	rec := get("If-None-Match", etag)
+	if rec.Code != http.StatusOK || rec.Header().Get("ETag") == etag {
+		t.Errorf("após mudança = %d, ETag %q; expect 200 com ETag novo", rec.Code, rec.Header().Get("ETag"))
+	}
+}
@@ -0,0 +1,218 @@
+package municipios
+
+import (
+	"embed"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// Tabela de municípios do IBGE embutida no binário (codigo,nome,uf,ddd).
+// O CSV é gerado pelo cmd/importmunicipios a partir da API de localidades do
+// IBGE (todos os municípios); rode go generate com acesso à internet para
+// atualizar. Os DDDs não vêm do IBGE: o gerador cruza o código de cada
+// município com uma base aberta de DDDs (veja o -ddd do cmd/importmunicipios).
+//
+//go:generate go run ../cmd/importmunicipios -out municipios.csv
+//go:embed municipios.csv
+var files embed.FS
+
+// Erros sentinela de ByCode e ByUF
+var (
+	ErrInvalidCode = errors.New("código IBGE inválido")
+	ErrNotFound    = errors.New("município não encontrado")
+	ErrInvalidUF   = errors.New("uf inválida")
+)
+
+// Municipio é uma linha da tabela; Codigo é o mesmo campo "ibge" da ViaCEP
+type Municipio struct {
+	Codigo string `json:"codigo"`
+	Nome   string `json:"nome"`
+	UF     string `json:"uf"`
+	Estado string `json:"estado"`
+	Regiao string `json:"regiao"`
+	DDD    string `json:"ddd,omitempty"` // Vazio se o CSV ainda não tem o DDD do município
+}
+
+// Um único DDD por município basta: o plano de numeração da Anatel divide o
+// país em áreas de numeração formadas por municípios inteiros, então todo
+// município pertence a exatamente uma área (um DDD).
+
+// estado guarda o que é comum a todos os municípios de uma UF
+type estado struct {
+	codigo string // 2 primeiros dígitos do código IBGE dos municípios
+	nome   string
+	regiao string
+}
+
+var estados = map[string]estado{
+	"RO": {"11", "Rondônia", "Norte"}, "AC": {"12", "Acre", "Norte"}, "AM": {"13", "Amazonas", "Norte"},
+	"RR": {"14", "Roraima", "Norte"}, "PA": {"15", "Pará", "Norte"}, "AP": {"16", "Amapá", "Norte"},
+	"TO": {"17", "Tocantins", "Norte"},
+	"MA": {"21", "Maranhão", "Nordeste"}, "PI": {"22", "Piauí", "Nordeste"}, "CE": {"23", "Ceará", "Nordeste"},
+	"RN": {"24", "Rio Grande do Norte", "Nordeste"}, "PB": {"25", "Paraíba", "Nordeste"},
+	"PE": {"26", "Pernambuco", "Nordeste"}, "AL": {"27", "Alagoas", "Nordeste"}, "SE": {"28", "Sergipe", "Nordeste"},
+	"BA": {"29", "Bahia", "Nordeste"},
+	"MG": {"31", "Minas Gerais", "Sudeste"}, "ES": {"32", "Espírito Santo", "Sudeste"},
+	"RJ": {"33", "Rio de Janeiro", "Sudeste"}, "SP": {"35", "São Paulo", "Sudeste"},
+	"PR": {"41", "Paraná", "Sul"}, "SC": {"42", "Santa Catarina", "Sul"}, "RS": {"43", "Rio Grande do Sul", "Sul"},
+	"MS": {"50", "Mato Grosso do Sul", "Centro-Oeste"}, "MT": {"51", "Mato Grosso", "Centro-Oeste"},
+	"GO": {"52", "Goiás", "Centro-Oeste"}, "DF": {"53", "Distrito Federal", "Centro-Oeste"},
+}
+
+// registry são os índices montados uma única vez, no primeiro uso
+type registry struct {
+	all    []Municipio            // Ordenados por código
+	byCode map[string]int         // codigo ==> posição em all
+	byUF   map[string][]Municipio // Ordenados por nome
+	byName map[string][]int       // Fold(nome) ==> posições em all
+}
+
+var load = sync.OnceValue(func() *registry {
+	r, err := parse("municipios.csv")
+	if err != nil {
+		panic(err) // Tabela embutida quebrada é erro de build, não de uso
+	}
+	return r
+})
+
+func parse(name string) (*registry, error) {
+	f, err := files.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	rows, err := csv.NewReader(f).ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("municipios: %s: %w", name, err)
+	}
+
+	r := &registry{byCode: make(map[string]int), byUF: make(map[string][]Municipio), byName: make(map[string][]int)}
+	for line, row := range rows[1:] { // Pula o cabeçalho
+		if len(row) != 4 {
+			return nil, fmt.Errorf("municipios: %s linha %d: esperadas 4 colunas", name, line+2)
+		}
+		m := Municipio{Codigo: row[0], Nome: row[1], UF: row[2], DDD: row[3]}
+		uf, ok := estados[m.UF]
+		if !ok || !validCode(m.Codigo) || !strings.HasPrefix(m.Codigo, uf.codigo) {
+			return nil, fmt.Errorf("municipios: %s linha %d: código %s/uf %s inválidos", name, line+2, m.Codigo, m.UF)
+		}
+		if m.DDD != "" && !validDDD(m.DDD) {
+			return nil, fmt.Errorf("municipios: %s linha %d: ddd %q inválido", name, line+2, m.DDD)
+		}
+		m.Estado, m.Regiao = uf.nome, uf.regiao
+		r.all = append(r.all, m)
+	}
+
+	slices.SortFunc(r.all, func(a, b Municipio) int { return strings.Compare(a.Codigo, b.Codigo) })
+	for i, m := range r.all {
+		if _, dup := r.byCode[m.Codigo]; dup {
+			return nil, fmt.Errorf("municipios: %s: código %s repetido", name, m.Codigo)
+		}
+		r.byCode[m.Codigo] = i
+		r.byName[Fold(m.Nome)] = append(r.byName[Fold(m.Nome)], i)
+		r.byUF[m.UF] = append(r.byUF[m.UF], m)
+	}
+	for _, list := range r.byUF {
+		slices.SortFunc(list, func(a, b Municipio) int { return strings.Compare(Fold(a.Nome), Fold(b.Nome)) })
+	}
+	return r, nil
+}
+
+// validCode confere se o código tem 7 dígitos
+func validCode(code string) bool {
+	if len(code) != 7 {
+		return false
+	}
+	for _, c := range code {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// validDDD confere o formato do DDD: 2 dígitos, de 11 a 99
+func validDDD(ddd string) bool {
+	return len(ddd) == 2 && ddd[0] >= '1' && ddd[0] <= '9' && ddd[1] >= '0' && ddd[1] <= '9'
+}
+
+// UFOfCode devolve a UF de um código IBGE de município pelos 2 primeiros dígitos
+func UFOfCode(code string) (string, bool) {
+	if !validCode(code) {
+		return "", false
+	}
+	for sigla, uf := range estados {
+		if strings.HasPrefix(code, uf.codigo) {
+			return sigla, true
+		}
+	}
+	return "", false
+}
+
+// ByCode busca o município pelo código IBGE (ex.: "3550308", o campo ibge da ViaCEP)
+func ByCode(code string) (Municipio, error) {
+	code = strings.TrimSpace(code)
+	if !validCode(code) {
+		return Municipio{}, ErrInvalidCode
+	}
+	r := load()
+	i, ok := r.byCode[code]
+	if !ok {
+		return Municipio{}, fmt.Errorf("%s: %w", code, ErrNotFound)
+	}
+	return r.all[i], nil
+}
+
+// ByUF lista os municípios de uma UF (maiúscula ou minúscula) em ordem alfabética
+func ByUF(uf string) ([]Municipio, error) {
+	uf = strings.ToUpper(strings.TrimSpace(uf))
+	if _, ok := estados[uf]; !ok {
+		return nil, ErrInvalidUF
+	}
+	return slices.Clone(load().byUF[uf]), nil
+}
+
+// ByName busca pelo nome sem diferenciar acentos nem maiúsculas
+// ("sao jose dos campos" encontra "São José dos Campos"). Como nomes se
+// repetem entre estados, devolve todos os municípios com aquele nome.
+func ByName(nome string) []Municipio {
+	r := load()
+	var result []Municipio
+	for _, i := range r.byName[Fold(nome)] {
+		result = append(result, r.all[i])
+	}
+	return result
+}
+
+// All devolve a tabela inteira, ordenada por código
+func All() []Municipio {
+	return slices.Clone(load().all)
+}
+
+// accents troca as letras acentuadas do português pela letra sem acento
+var accents = strings.NewReplacer(
+	"á", "a", "à", "a", "â", "a", "ã", "a", "ä", "a",
+	"é", "e", "è", "e", "ê", "e", "ë", "e",
+	"í", "i", "ì", "i", "î", "i", "ï", "i",
+	"ó", "o", "ò", "o", "ô", "o", "õ", "o", "ö", "o",
+	"ú", "u", "ù", "u", "û", "u", "ü", "u",
+	"ç", "c", "ñ", "n",
+)
+
+// Fold deixa o texto em minúsculas, sem acentos e com espaços simples,
+// para comparar nomes: Fold("  São  PAULO") == "sao paulo".
+// Também remove acentos decompostos (NFD), como "a" seguido de U+0301.
+func Fold(s string) string {
+	s = strings.Map(func(r rune) rune {
+		if r >= 0x300 && r <= 0x36f { // Diacríticos combinantes
+			return -1
+		}
+		return r
+	}, strings.ToLower(s))
+	return accents.Replace(strings.Join(strings.Fields(s), " "))
+}
@@ -0,0 +1,194 @@
+package municipios
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestByCode(t *testing.T) {
+	tests := []struct {
+		code   string
+		expect string
+		err    error
+	}{
+		{"3550308", "São Paulo", nil},
+		{" 3543402 ", "Ribeirão Preto", nil},
+		{"5300108", "Brasília", nil},
+		{"3550309", "", ErrNotFound},
+		{"355030", "", ErrInvalidCode},
+		{"35503O8", "", ErrInvalidCode},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.code, func(t *testing.T) {
+			m, err := ByCode(tt.code)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("ByCode(%q) erro = %v; expect %v", tt.code, err, tt.err)
+			}
+			if m.Nome != tt.expect {
+				t.Errorf("ByCode(%q) = %q; expect %q", tt.code, m.Nome, tt.expect)
+			}
+		})
+	}
+}
+
+func TestByCodeFields(t *testing.T) {
+	m, err := ByCode("2927408")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expect := Municipio{Codigo: "2927408", Nome: "Salvador", UF: "BA", Estado: "Bahia", Regiao: "Nordeste", DDD: "71"}
+	if m != expect {
+		t.Errorf("ByCode(2927408) = %+v; expect %+v", m, expect)
+	}
+}
+
+func TestByUF(t *testing.T) {
+	list, err := ByUF("sc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// A lista vem em ordem alfabética e traz estes municípios, nesta ordem
+	expect := []string{"Blumenau", "Chapecó", "Criciúma", "Florianópolis", "Joinville"}
+	next := 0
+	for i, m := range list {
+		if m.UF != "SC" {
+			t.Errorf("ByUF(sc)[%d] = %s/%s; expect UF SC", i, m.Nome, m.UF)
+		}
+		if i > 0 && Fold(list[i-1].Nome) > Fold(m.Nome) {
+			t.Errorf("ByUF(sc) fora de ordem: %s antes de %s", list[i-1].Nome, m.Nome)
+		}
+		if next < len(expect) && m.Nome == expect[next] {
+			next++
+		}
+	}
+	if next != len(expect) {
+		t.Errorf("ByUF(sc) sem %s", expect[next])
+	}
+
+	if _, err := ByUF("XX"); !errors.Is(err, ErrInvalidUF) {
+		t.Errorf("ByUF(XX) erro = %v; expect ErrInvalidUF", err)
+	}
+
+	// O chamador pode alterar a lista sem estragar o índice
+	first := list[0].Nome
+	list[0].Nome = "alterado"
+	if again, _ := ByUF("SC"); again[0].Nome != first {
+		t.Errorf("ByUF devolveu o slice interno")
+	}
+}
+
+func TestByName(t *testing.T) {
+	tests := []struct {
+		nome   string
+		expect string // Código, vazio se não existe
+	}{
+		{"São Paulo", "3550308"},
+		{"sao paulo", "3550308"},
+		{"  SÃO   JOSÉ DOS CAMPOS ", "3549904"},
+		{"Sa\u0303o Lui\u0301s", "2111300"}, // Acentos decompostos (NFD)
+		{"goiania", "5208707"},
+		{"Vitoria", "3205309"}, // Não confunde com Vitória da Conquista
+		{"Paulo", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.nome, func(t *testing.T) {
+			list := ByName(tt.nome)
+			if tt.expect == "" {
+				if len(list) != 0 {
+					t.Errorf("ByName(%q) = %v; expect nenhum", tt.nome, list)
+				}
+				return
+			}
+			if len(list) != 1 || list[0].Codigo != tt.expect {
+				t.Errorf("ByName(%q) = %v; expect %s", tt.nome, list, tt.expect)
+			}
+		})
+	}
+}
+
+func TestFold(t *testing.T) {
+	tests := map[string]string{
+		"São Paulo":            "sao paulo",
+		"JOÃO  PESSOA":         "joao pessoa",
+		"Foz do Iguaçu":        "foz do iguacu",
+		"Ji-Paraná":            "ji-parana",
+		"Sa\u0303o Lui\u0301s": "sao luis",
+	}
+	for input, expect := range tests {
+		if got := Fold(input); got != expect {
+			t.Errorf("Fold(%q) = %q; expect %q", input, got, expect)
+		}
+	}
+}
+
+// checkDigit calcula o dígito verificador do código IBGE de município
+// (pesos 1 e 2 alternados nos 6 primeiros dígitos, somando os algarismos)
+func checkDigit(code string) byte {
+	sum := 0
+	for i := range 6 {
+		n := int(code[i]-'0') * (1 + i%2)
+		sum += n/10 + n%10
+	}
+	return byte('0' + (10-sum%10)%10)
+}
+
+// TestTable confere a tabela embutida: dígito verificador, UFs e DDD (quando presente)
+func TestTable(t *testing.T) {
+	all := All()
+	if len(all) == 0 {
+		t.Fatal("tabela vazia")
+	}
+
+	ufs := map[string]bool{}
+	for _, m := range all {
+		if checkDigit(m.Codigo) != m.Codigo[6] {
+			t.Errorf("%s (%s): dígito verificador inválido", m.Codigo, m.Nome)
+		}
+		if m.DDD != "" && !validDDD(m.DDD) {
+			t.Errorf("%s (%s): ddd %q inválido", m.Codigo, m.Nome, m.DDD)
+		}
+		ufs[m.UF] = true
+	}
+	if len(ufs) != len(estados) {
+		t.Errorf("tabela cobre %d UFs; expect %d", len(ufs), len(estados))
+	}
+}
+
+func TestUFOfCode(t *testing.T) {
+	tests := map[string]string{"3550308": "SP", "5300108": "DF", "1100205": "RO", "9900000": "", "355030": ""}
+	for code, expect := range tests {
+		if got, ok := UFOfCode(code); got != expect || ok != (expect != "") {
+			t.Errorf("UFOfCode(%q) = %q, %v; expect %q", code, got, ok, expect)
+		}
+	}
+}
+
+// TestTableComplete confere que a tabela é a do IBGE inteira, com DDD em todos
+// os municípios, e não só as cidades grandes
+func TestTableComplete(t *testing.T) {
+	all := All()
+	if len(all) < 5500 {
+		t.Skipf("municipios.csv tem %d municípios: rode go generate (precisa de internet) para gravar a tabela do IBGE", len(all))
+	}
+
+	for _, m := range all {
+		if m.DDD == "" {
+			t.Errorf("%s (%s) sem DDD", m.Codigo, m.Nome)
+		}
+	}
+
+	// Municípios pequenos, fora das capitais e cidades grandes
+	tests := []Municipio{
+		{Codigo: "1100015", Nome: "Alta Floresta D'Oeste", UF: "RO"},
+		{Codigo: "1200013", Nome: "Acrelândia", UF: "AC"},
+		{Codigo: "5101837", Nome: "Boa Esperança do Norte", UF: "MT"},
+	}
+	for _, tt := range tests {
+		m, err := ByCode(tt.Codigo)
+		if err != nil || m.Nome != tt.Nome || m.UF != tt.UF {
+			t.Errorf("ByCode(%s) = %s/%s, %v; expect %s/%s", tt.Codigo, m.Nome, m.UF, err, tt.Nome, tt.UF)
+		}
+	}
+}
@@ -0,0 +1,429 @@
+// Serviço gRPC de busca de endereço por CEP (mesma lógica do BuscaCepHandler).
+//
+// Para gerar o código em ../pb (a partir de 5_cep-handler):
+//   protoc --go_out=. --go_opt=module=GoProject/1_moduleFoundation/5_cep-handler \
+//          --go-grpc_out=. --go-grpc_opt=module=GoProject/1_moduleFoundation/5_cep-handler \
+//          proto/address.proto
+
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.9
+// 	protoc        (unknown)
+// source: proto/address.proto
+
+package pb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type GetAddressRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Cep           string                 `protobuf:"bytes,1,opt,name=cep,proto3" json:"cep,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetAddressRequest) Reset() {
+	*x = GetAddressRequest{}
+	mi := &file_proto_address_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetAddressRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetAddressRequest) ProtoMessage() {}
+
+func (x *GetAddressRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_address_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetAddressRequest.ProtoReflect.Descriptor instead.
+func (*GetAddressRequest) Descriptor() ([]byte, []int) {
+	return file_proto_address_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *GetAddressRequest) GetCep() string {
+	if x != nil {
+		return x.Cep
+	}
+	return ""
+}
+
+type GetAddressesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Ceps          []string               `protobuf:"bytes,1,rep,name=ceps,proto3" json:"ceps,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetAddressesRequest) Reset() {
+	*x = GetAddressesRequest{}
+	mi := &file_proto_address_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetAddressesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetAddressesRequest) ProtoMessage() {}
+
+func (x *GetAddressesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_address_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetAddressesRequest.ProtoReflect.Descriptor instead.
+func (*GetAddressesRequest) Descriptor() ([]byte, []int) {
+	return file_proto_address_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *GetAddressesRequest) GetCeps() []string {
+	if x != nil {
+		return x.Ceps
+	}
+	return nil
+}
+
+// Address tem os mesmos campos da struct getCep.ViaCEP
+type Address struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Cep           string                 `protobuf:"bytes,1,opt,name=cep,proto3" json:"cep,omitempty"`
+	Logradouro    string                 `protobuf:"bytes,2,opt,name=logradouro,proto3" json:"logradouro,omitempty"`
+	Complemento   string                 `protobuf:"bytes,3,opt,name=complemento,proto3" json:"complemento,omitempty"`
+	Unidade       string                 `protobuf:"bytes,4,opt,name=unidade,proto3" json:"unidade,omitempty"`
+	Bairro        string                 `protobuf:"bytes,5,opt,name=bairro,proto3" json:"bairro,omitempty"`
+	Localidade    string                 `protobuf:"bytes,6,opt,name=localidade,proto3" json:"localidade,omitempty"`
+	Uf            string                 `protobuf:"bytes,7,opt,name=uf,proto3" json:"uf,omitempty"`
+	Estado        string                 `protobuf:"bytes,8,opt,name=estado,proto3" json:"estado,omitempty"`
+	Regiao        string                 `protobuf:"bytes,9,opt,name=regiao,proto3" json:"regiao,omitempty"`
+	Ibge          string                 `protobuf:"bytes,10,opt,name=ibge,proto3" json:"ibge,omitempty"`
+	Gia           string                 `protobuf:"bytes,11,opt,name=gia,proto3" json:"gia,omitempty"`
+	Ddd           string                 `protobuf:"bytes,12,opt,name=ddd,proto3" json:"ddd,omitempty"`
+	Siafi         string                 `protobuf:"bytes,13,opt,name=siafi,proto3" json:"siafi,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Address) Reset() {
+	*x = Address{}
+	mi := &file_proto_address_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Address) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Address) ProtoMessage() {}
+
+func (x *Address) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_address_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Address.ProtoReflect.Descriptor instead.
+func (*Address) Descriptor() ([]byte, []int) {
+	return file_proto_address_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *Address) GetCep() string {
+	if x != nil {
+		return x.Cep
+	}
+	return ""
+}
+
+func (x *Address) GetLogradouro() string {
+	if x != nil {
+		return x.Logradouro
+	}
+	return ""
+}
+
+func (x *Address) GetComplemento() string {
+	if x != nil {
+		return x.Complemento
+	}
+	return ""
+}
+
+func (x *Address) GetUnidade() string {
+	if x != nil {
+		return x.Unidade
+	}
+	return ""
+}
+
+func (x *Address) GetBairro() string {
+	if x != nil {
+		return x.Bairro
+	}
+	return ""
+}
+
+func (x *Address) GetLocalidade() string {
+	if x != nil {
+		return x.Localidade
+	}
+	return ""
+}
+
+func (x *Address) GetUf() string {
+	if x != nil {
+		return x.Uf
+	}
+	return ""
+}
+
+func (x *Address) GetEstado() string {
+	if x != nil {
+		return x.Estado
+	}
+	return ""
+}
+
+func (x *Address) GetRegiao() string {
+	if x != nil {
+		return x.Regiao
+	}
+	return ""
+}
+
+func (x *Address) GetIbge() string {
+	if x != nil {
+		return x.Ibge
+	}
+	return ""
+}
+
+func (x *Address) GetGia() string {
+	if x != nil {
+		return x.Gia
+	}
+	return ""
+}
+
+func (x *Address) GetDdd() string {
+	if x != nil {
+		return x.Ddd
+	}
+	return ""
+}
+
+func (x *Address) GetSiafi() string {
+	if x != nil {
+		return x.Siafi
+	}
+	return ""
+}
+
+type AddressResult struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Index         int32                  `protobuf:"varint,1,opt,name=index,proto3" json:"index,omitempty"` // Posição do CEP em GetAddressesRequest.ceps
+	Cep           string                 `protobuf:"bytes,2,opt,name=cep,proto3" json:"cep,omitempty"`      // CEP como foi enviado
+	Address       *Address               `protobuf:"bytes,3,opt,name=address,proto3" json:"address,omitempty"`
+	Code          uint32                 `protobuf:"varint,4,opt,name=code,proto3" json:"code,omitempty"` // Código gRPC do erro (google.golang.org/grpc/codes); 0 = OK
+	Error         string                 `protobuf:"bytes,5,opt,name=error,proto3" json:"error,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AddressResult) Reset() {
+	*x = AddressResult{}
+	mi := &file_proto_address_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AddressResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AddressResult) ProtoMessage() {}
+
+func (x *AddressResult) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_address_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AddressResult.ProtoReflect.Descriptor instead.
+func (*AddressResult) Descriptor() ([]byte, []int) {
+	return file_proto_address_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *AddressResult) GetIndex() int32 {
+	if x != nil {
+		return x.Index
+	}
+	return 0
+}
+
+func (x *AddressResult) GetCep() string {
+	if x != nil {
+		return x.Cep
+	}
+	return ""
+}
+
+func (x *AddressResult) GetAddress() *Address {
+	if x != nil {
+		return x.Address
+	}
+	return nil
+}
+
+func (x *AddressResult) GetCode() uint32 {
+	if x != nil {
+		return x.Code
+	}
+	return 0
+}
+
+func (x *AddressResult) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+var File_proto_address_proto protoreflect.FileDescriptor
+
+const file_proto_address_proto_rawDesc = "" +
+	"\n" +
+	"\x13proto/address.proto\x12\x06cep.v1\"%\n" +
+	"\x11GetAddressRequest\x12\x10\n" +
+	"\x03cep\x18\x01 \x01(\tR\x03cep\")\n" +
+	"\x13GetAddressesRequest\x12\x12\n" +
+	"\x04ceps\x18\x01 \x03(\tR\x04ceps\"\xbd\x02\n" +
+	"\aAddress\x12\x10\n" +
+	"\x03cep\x18\x01 \x01(\tR\x03cep\x12\x1e\n" +
+	"\n" +
+	"logradouro\x18\x02 \x01(\tR\n" +
+	"logradouro\x12 \n" +
+	"\vcomplemento\x18\x03 \x01(\tR\vcomplemento\x12\x18\n" +
+	"\aunidade\x18\x04 \x01(\tR\aunidade\x12\x16\n" +
+	"\x06bairro\x18\x05 \x01(\tR\x06bairro\x12\x1e\n" +
+	"\n" +
+	"localidade\x18\x06 \x01(\tR\n" +
+	"localidade\x12\x0e\n" +
+	"\x02uf\x18\a \x01(\tR\x02uf\x12\x16\n" +
+	"\x06estado\x18\b \x01(\tR\x06estado\x12\x16\n" +
+	"\x06regiao\x18\t \x01(\tR\x06regiao\x12\x12\n" +
+	"\x04ibge\x18\n" +
+	" \x01(\tR\x04ibge\x12\x10\n" +
+	"\x03gia\x18\v \x01(\tR\x03gia\x12\x10\n" +
+	"\x03ddd\x18\f \x01(\tR\x03ddd\x12\x14\n" +
+	"\x05siafi\x18\r \x01(\tR\x05siafi\"\x8c\x01\n" +
+	"\rAddressResult\x12\x14\n" +
+	"\x05index\x18\x01 \x01(\x05R\x05index\x12\x10\n" +
+	"\x03cep\x18\x02 \x01(\tR\x03cep\x12)\n" +
+	"\aaddress\x18\x03 \x01(\v2\x0f.cep.v1.AddressR\aaddress\x12\x12\n" +
+	"\x04code\x18\x04 \x01(\rR\x04code\x12\x14\n" +
+	"\x05error\x18\x05 \x01(\tR\x05error2\x90\x01\n" +
+	"\x0eAddressService\x128\n" +
+	"\n" +
+	"GetAddress\x12\x19.cep.v1.GetAddressRequest\x1a\x0f.cep.v1.Address\x12D\n" +
+	"\fGetAddresses\x12\x1b.cep.v1.GetAddressesRequest\x1a\x15.cep.v1.AddressResult0\x01B/Z-GoProject/1_moduleFoundation/5_cep-handler/pbb\x06proto3"
+
+var (
+	file_proto_address_proto_rawDescOnce sync.Once
+	file_proto_address_proto_rawDescData []byte
+)
+
+func file_proto_address_proto_rawDescGZIP() []byte {
+	file_proto_address_proto_rawDescOnce.Do(func() {
+		file_proto_address_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_proto_address_proto_rawDesc), len(file_proto_address_proto_rawDesc)))
+	})
+	return file_proto_address_proto_rawDescData
+}
+
+var file_proto_address_proto_msgTypes = make([]protoimpl.MessageInfo, 4)
+var file_proto_address_proto_goTypes = []any{
+	(*GetAddressRequest)(nil),   // 0: cep.v1.GetAddressRequest
+	(*GetAddressesRequest)(nil), // 1: cep.v1.GetAddressesRequest
+	(*Address)(nil),             // 2: cep.v1.Address
+	(*AddressResult)(nil),       // 3: cep.v1.AddressResult
+}
+var file_proto_address_proto_depIdxs = []int32{
+	2, // 0: cep.v1.AddressResult.address:type_name -> cep.v1.Address
+	0, // 1: cep.v1.AddressService.GetAddress:input_type -> cep.v1.GetAddressRequest
+	1, // 2: cep.v1.AddressService.GetAddresses:input_type -> cep.v1.GetAddressesRequest
+	2, // 3: cep.v1.AddressService.GetAddress:output_type -> cep.v1.Address
+	3, // 4: cep.v1.AddressService.GetAddresses:output_type -> cep.v1.AddressResult
+	3, // [3:5] is the sub-list for method output_type
+	1, // [1:3] is the sub-list for method input_type
+	1, // [1:1] is the sub-list for extension type_name
+	1, // [1:1] is the sub-list for extension extendee
+	0, // [0:1] is the sub-list for field type_name
+}
+
+func init() { file_proto_address_proto_init() }
+func file_proto_address_proto_init() {
+	if File_proto_address_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_proto_address_proto_rawDesc), len(file_proto_address_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   4,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_proto_address_proto_goTypes,
+		DependencyIndexes: file_proto_address_proto_depIdxs,
+		MessageInfos:      file_proto_address_proto_msgTypes,
+	}.Build()
+	File_proto_address_proto = out.File
+	file_proto_address_proto_goTypes = nil
+	file_proto_address_proto_depIdxs = nil
+}
@@ -0,0 +1,178 @@
+// Serviço gRPC de busca de endereço por CEP (mesma lógica do BuscaCepHandler).
+//
+// Para gerar o código em ../pb (a partir de 5_cep-handler):
+//   protoc --go_out=. --go_opt=module=GoProject/1_moduleFoundation/5_cep-handler \
+//          --go-grpc_out=. --go-grpc_opt=module=GoProject/1_moduleFoundation/5_cep-handler \
+//          proto/address.proto
+
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             (unknown)
+// source: proto/address.proto
+
+package pb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	AddressService_GetAddress_FullMethodName   = "/cep.v1.AddressService/GetAddress"
+	AddressService_GetAddresses_FullMethodName = "/cep.v1.AddressService/GetAddresses"
+)
+
+// AddressServiceClient is the client API for AddressService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type AddressServiceClient interface {
+	// GetAddress busca um CEP. Erros: INVALID_ARGUMENT (CEP inválido),
+	// NOT_FOUND, UNAVAILABLE (ViaCEP/BrasilAPI fora) e DEADLINE_EXCEEDED.
+	GetAddress(ctx context.Context, in *GetAddressRequest, opts ...grpc.CallOption) (*Address, error)
+	// GetAddresses busca vários CEPs e envia cada resultado assim que fica pronto
+	// (fora de ordem: use o campo index). Erros de um CEP não encerram o stream.
+	GetAddresses(ctx context.Context, in *GetAddressesRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[AddressResult], error)
+}
+
+type addressServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewAddressServiceClient(cc grpc.ClientConnInterface) AddressServiceClient {
+	return &addressServiceClient{cc}
+}
+
+func (c *addressServiceClient) GetAddress(ctx context.Context, in *GetAddressRequest, opts ...grpc.CallOption) (*Address, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Address)
+	err := c.cc.Invoke(ctx, AddressService_GetAddress_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *addressServiceClient) GetAddresses(ctx context.Context, in *GetAddressesRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[AddressResult], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &AddressService_ServiceDesc.Streams[0], AddressService_GetAddresses_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[GetAddressesRequest, AddressResult]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type AddressService_GetAddressesClient = grpc.ServerStreamingClient[AddressResult]
+
+// AddressServiceServer is the server API for AddressService service.
+// All implementations must embed UnimplementedAddressServiceServer
+// for forward compatibility.
+type AddressServiceServer interface {
+	// GetAddress busca um CEP. Erros: INVALID_ARGUMENT (CEP inválido),
+	// NOT_FOUND, UNAVAILABLE (ViaCEP/BrasilAPI fora) e DEADLINE_EXCEEDED.
+	GetAddress(context.Context, *GetAddressRequest) (*Address, error)
+	// GetAddresses busca vários CEPs e envia cada resultado assim que fica pronto
+	// (fora de ordem: use o campo index). Erros de um CEP não encerram o stream.
+	GetAddresses(*GetAddressesRequest, grpc.ServerStreamingServer[AddressResult]) error
+	mustEmbedUnimplementedAddressServiceServer()
+}
+
+// UnimplementedAddressServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedAddressServiceServer struct{}
+
+func (UnimplementedAddressServiceServer) GetAddress(context.Context, *GetAddressRequest) (*Address, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetAddress not implemented")
+}
+func (UnimplementedAddressServiceServer) GetAddresses(*GetAddressesRequest, grpc.ServerStreamingServer[AddressResult]) error {
+	return status.Errorf(codes.Unimplemented, "method GetAddresses not implemented")
+}
+func (UnimplementedAddressServiceServer) mustEmbedUnimplementedAddressServiceServer() {}
+func (UnimplementedAddressServiceServer) testEmbeddedByValue()                        {}
+
+// UnsafeAddressServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to AddressServiceServer will
+// result in compilation errors.
+type UnsafeAddressServiceServer interface {
+	mustEmbedUnimplementedAddressServiceServer()
+}
+
+func RegisterAddressServiceServer(s grpc.ServiceRegistrar, srv AddressServiceServer) {
+	// If the following call pancis, it indicates UnimplementedAddressServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&AddressService_ServiceDesc, srv)
+}
+
+func _AddressService_GetAddress_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetAddressRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AddressServiceServer).GetAddress(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AddressService_GetAddress_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AddressServiceServer).GetAddress(ctx, req.(*GetAddressRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AddressService_GetAddresses_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(GetAddressesRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(AddressServiceServer).GetAddresses(m, &grpc.GenericServerStream[GetAddressesRequest, AddressResult]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type AddressService_GetAddressesServer = grpc.ServerStreamingServer[AddressResult]
+
+// AddressService_ServiceDesc is the grpc.ServiceDesc for AddressService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var AddressService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "cep.v1.AddressService",
+	HandlerType: (*AddressServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetAddress",
+			Handler:    _AddressService_GetAddress_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "GetAddresses",
+			Handler:       _AddressService_GetAddresses_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "proto/address.proto",
+}
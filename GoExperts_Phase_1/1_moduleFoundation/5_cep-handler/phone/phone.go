@@ -0,0 +1,165 @@
+package phone
+
+import (
+	"GoProject/1_moduleFoundation/5_cep-handler/getCep"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Formatos aceitos por Parse (separadores " ()-." são ignorados):
+//
+//	(11) 91234-5678     11912345678
+//	+55 11 91234-5678   5511912345678
+//	011 91234-5678      (0 de longa distância)
+//	0xx11 91234-5678    0 15 11 91234-5678 (código da operadora)
+
+// Erros sentinela de Parse e CheckAddress
+var (
+	ErrInvalidPhone = errors.New("telefone inválido")
+	ErrInvalidDDD   = errors.New("ddd inválido")
+	ErrDDDMismatch  = errors.New("ddd do telefone diferente do ddd do endereço")
+)
+
+// Kind diferencia celular de fixo
+type Kind int
+
+const (
+	Landline Kind = iota + 1 // Fixo: 8 dígitos começando com 2 a 5
+	Mobile                   // Celular: 9 dígitos começando com 9
+)
+
+func (k Kind) String() string {
+	switch k {
+	case Landline:
+		return "fixo"
+	case Mobile:
+		return "celular"
+	}
+	return "desconhecido"
+}
+
+// MarshalText faz o Kind sair como "fixo"/"celular" no JSON
+func (k Kind) MarshalText() ([]byte, error) {
+	return []byte(k.String()), nil
+}
+
+// ddds são os códigos de área válidos (67) e a UF de cada um.
+// O 61 também atende parte de GO, mas a UF principal é o DF.
+var ddds = map[string]string{
+	"11": "SP", "12": "SP", "13": "SP", "14": "SP", "15": "SP", "16": "SP", "17": "SP", "18": "SP", "19": "SP",
+	"21": "RJ", "22": "RJ", "24": "RJ", "27": "ES", "28": "ES",
+	"31": "MG", "32": "MG", "33": "MG", "34": "MG", "35": "MG", "37": "MG", "38": "MG",
+	"41": "PR", "42": "PR", "43": "PR", "44": "PR", "45": "PR", "46": "PR", "47": "SC", "48": "SC", "49": "SC",
+	"51": "RS", "53": "RS", "54": "RS", "55": "RS",
+	"61": "DF", "62": "GO", "63": "TO", "64": "GO", "65": "MT", "66": "MT", "67": "MS", "68": "AC", "69": "RO",
+	"71": "BA", "73": "BA", "74": "BA", "75": "BA", "77": "BA", "79": "SE",
+	"81": "PE", "82": "AL", "83": "PB", "84": "RN", "85": "CE", "86": "PI", "87": "PE", "88": "CE", "89": "PI",
+	"91": "PA", "92": "AM", "93": "PA", "94": "PA", "95": "RR", "96": "AP", "97": "AM", "98": "MA", "99": "MA",
+}
+
+// ValidDDD informa se o código de área existe
+func ValidDDD(ddd string) bool {
+	_, ok := ddds[ddd]
+	return ok
+}
+
+// Phone é um telefone brasileiro já validado
+type Phone struct {
+	DDD    string `json:"ddd"`
+	Number string `json:"number"` // Só dígitos, sem o DDD
+	Kind   Kind   `json:"kind"`
+}
+
+// Parse aceita os formatos comuns de telefone com DDD e devolve o número validado
+func Parse(s string) (Phone, error) {
+	digits, err := onlyDigits(s)
+	if err != nil {
+		return Phone{}, err
+	}
+
+	switch {
+	case strings.HasPrefix(strings.TrimSpace(s), "+"):
+		if !strings.HasPrefix(digits, "55") {
+			return Phone{}, fmt.Errorf("%w: só números do Brasil (+55)", ErrInvalidPhone)
+		}
+		digits = digits[2:]
+	case strings.HasPrefix(digits, "55") && (len(digits) == 12 || len(digits) == 13):
+		digits = digits[2:]
+	case strings.HasPrefix(digits, "0"):
+		digits = digits[1:]
+		if len(digits) == 12 || len(digits) == 13 {
+			digits = digits[2:] // Código da operadora
+		}
+	}
+
+	if len(digits) != 10 && len(digits) != 11 {
+		return Phone{}, fmt.Errorf("%w: esperados DDD + 8 ou 9 dígitos", ErrInvalidPhone)
+	}
+
+	p := Phone{DDD: digits[:2], Number: digits[2:]}
+	if !ValidDDD(p.DDD) {
+		return Phone{}, fmt.Errorf("%s: %w", p.DDD, ErrInvalidDDD)
+	}
+
+	switch first := p.Number[0]; {
+	case len(p.Number) == 9 && first == '9':
+		p.Kind = Mobile
+	case len(p.Number) == 8 && first >= '2' && first <= '5':
+		p.Kind = Landline
+	case len(p.Number) == 8 && first >= '6':
+		return Phone{}, fmt.Errorf("%w: celular sem o nono dígito", ErrInvalidPhone)
+	default:
+		return Phone{}, fmt.Errorf("%w: número %s não é fixo nem celular", ErrInvalidPhone, p.Number)
+	}
+	return p, nil
+}
+
+// onlyDigits tira os separadores e o prefixo "0xx"; qualquer outro caractere é erro
+func onlyDigits(s string) (string, error) {
+	s = strings.TrimSpace(s)
+	if len(s) >= 3 && strings.EqualFold(s[:3], "0xx") {
+		s = s[3:]
+	}
+	s = strings.TrimPrefix(s, "+")
+
+	var b strings.Builder
+	for _, c := range s {
+		switch {
+		case c >= '0' && c <= '9':
+			b.WriteRune(c)
+		case strings.ContainsRune(" ()-.", c):
+		default:
+			return "", fmt.Errorf("%w: caractere %q", ErrInvalidPhone, c)
+		}
+	}
+	return b.String(), nil
+}
+
+// E164 devolve o número no padrão internacional: +5511912345678
+func (p Phone) E164() string {
+	return "+55" + p.DDD + p.Number
+}
+
+// String formata no padrão nacional: (11) 91234-5678 ou (11) 3456-7890
+func (p Phone) String() string {
+	if len(p.Number) < 8 {
+		return p.DDD + p.Number
+	}
+	split := len(p.Number) - 4
+	return "(" + p.DDD + ") " + p.Number[:split] + "-" + p.Number[split:]
+}
+
+// UF devolve o estado do DDD do telefone
+func (p Phone) UF() string {
+	return ddds[p.DDD]
+}
+
+// CheckAddress confere o DDD do telefone com o campo ddd do endereço da ViaCEP.
+// Endereço sem DDD não tem como ser conferido e não é erro.
+func CheckAddress(p Phone, addr *getCep.ViaCEP) error {
+	if addr == nil || addr.Ddd == "" || addr.Ddd == p.DDD {
+		return nil
+	}
+	return fmt.Errorf("telefone %s, endereço %s (ddd %s): %w", p, addr.Cep, addr.Ddd, ErrDDDMismatch)
+}
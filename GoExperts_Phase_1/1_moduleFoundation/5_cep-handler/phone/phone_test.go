@@ -0,0 +1,108 @@
+package phone
+
+import (
+	"GoProject/1_moduleFoundation/5_cep-handler/getCep"
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		input string
+		e164  string
+		kind  Kind
+		err   error
+	}{
+		{"(11) 91234-5678", "+5511912345678", Mobile, nil},
+		{"11912345678", "+5511912345678", Mobile, nil},
+		{"+55 11 91234-5678", "+5511912345678", Mobile, nil},
+		{"5511912345678", "+5511912345678", Mobile, nil},
+		{"011 91234-5678", "+5511912345678", Mobile, nil},
+		{"0xx16 3456-7890", "+551634567890", Landline, nil},
+		{"0 15 21 2345.6789", "+552123456789", Landline, nil},
+		{"55 3222-1234", "+555532221234", Landline, nil}, // DDD 55 (RS) sem o +55
+		{"+1 202 555 0100", "", 0, ErrInvalidPhone},
+		{"(20) 91234-5678", "", 0, ErrInvalidDDD},
+		{"(11) 8123-4567", "", 0, ErrInvalidPhone}, // Celular antigo, sem o 9
+		{"(11) 1234-5678", "", 0, ErrInvalidPhone},
+		{"(11) 81234-5678", "", 0, ErrInvalidPhone},
+		{"91234-5678", "", 0, ErrInvalidPhone}, // Sem DDD
+		{"11 9123a-5678", "", 0, ErrInvalidPhone},
+		{"", "", 0, ErrInvalidPhone},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			p, err := Parse(tt.input)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("Parse(%q) erro = %v; expect %v", tt.input, err, tt.err)
+			}
+			if err != nil {
+				return
+			}
+			if p.E164() != tt.e164 || p.Kind != tt.kind {
+				t.Errorf("Parse(%q) = %s (%s); expect %s (%s)", tt.input, p.E164(), p.Kind, tt.e164, tt.kind)
+			}
+		})
+	}
+}
+
+func TestPhoneFormat(t *testing.T) {
+	mobile, _ := Parse("+5511912345678")
+	landline, _ := Parse("1634567890")
+
+	if got := mobile.String(); got != "(11) 91234-5678" {
+		t.Errorf("String() = %q; expect (11) 91234-5678", got)
+	}
+	if got := landline.String(); got != "(16) 3456-7890" {
+		t.Errorf("String() = %q; expect (16) 3456-7890", got)
+	}
+	if mobile.UF() != "SP" {
+		t.Errorf("UF() = %q; expect SP", mobile.UF())
+	}
+
+	data, _ := json.Marshal(mobile)
+	if expect := `{"ddd":"11","number":"912345678","kind":"celular"}`; string(data) != expect {
+		t.Errorf("json = %s; expect %s", data, expect)
+	}
+}
+
+func TestValidDDD(t *testing.T) {
+	if len(ddds) != 67 {
+		t.Errorf("%d DDDs; expect 67", len(ddds))
+	}
+	for _, ddd := range []string{"11", "61", "99"} {
+		if !ValidDDD(ddd) {
+			t.Errorf("ValidDDD(%s) = false", ddd)
+		}
+	}
+	for _, ddd := range []string{"10", "20", "23", "52", "1", "011"} {
+		if ValidDDD(ddd) {
+			t.Errorf("ValidDDD(%s) = true", ddd)
+		}
+	}
+}
+
+func TestCheckAddress(t *testing.T) {
+	p, _ := Parse("(16) 99123-4567")
+
+	tests := []struct {
+		name string
+		addr *getCep.ViaCEP
+		err  error
+	}{
+		{"mesmo ddd", &getCep.ViaCEP{Cep: "14093-070", Ddd: "16"}, nil},
+		{"ddd diferente", &getCep.ViaCEP{Cep: "01001-000", Ddd: "11"}, ErrDDDMismatch},
+		{"endereço sem ddd", &getCep.ViaCEP{Cep: "01001-000"}, nil},
+		{"sem endereço", nil, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := CheckAddress(p, tt.addr); !errors.Is(err, tt.err) {
+				t.Errorf("CheckAddress() = %v; expect %v", err, tt.err)
+			}
+		})
+	}
+}
@@ -1,29 +1,33 @@
 package main
 
-import "net/http"
+import (
+	"GoProject/1_moduleFoundation/6_serverMux/rateLimit"
+	"net/http"
+)
 
 func main() {
-    mux := http.NewServeMux()
+	mux := http.NewServeMux()
 	mux2 := http.NewServeMux()
 
-    mux.HandleFunc("/", SchedulerHandler)
-    mux2.HandleFunc("/", AppointmentHandler)
+	mux.HandleFunc("/", SchedulerHandler)
+	mux2.HandleFunc("/", AppointmentHandler)
 
+	// 60 requisições por minuto por IP, com rajadas de até 10 (429 quando passar).
+	// Os dois servidores dividem o limiter: o limite vale para o cliente, não por porta.
+	limiter := rateLimit.New(rateLimit.Options{Default: rateLimit.PerMinute(60, 10)})
 
 	go func() {
-		http.ListenAndServe(":8080", mux)
+		http.ListenAndServe(":8080", limiter.Handler(mux))
 	}()
 
-	http.ListenAndServe(":8081", mux2)
+	http.ListenAndServe(":8081", limiter.Handler(mux2))
 
 }
 
 func SchedulerHandler(w http.ResponseWriter, r *http.Request) {
-    w.Write([]byte("Scheduler Handler"))
+	w.Write([]byte("Scheduler Handler"))
 }
 
 func AppointmentHandler(w http.ResponseWriter, r *http.Request) {
-    w.Write([]byte("Appointment Handler"))
+	w.Write([]byte("Appointment Handler"))
 }
-
-
@@ -0,0 +1,213 @@
+package rateLimit
+
+import (
+	"container/list"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Rate limit por cliente com token bucket: cada cliente tem um balde com até
+// Burst fichas, que se enchem a Rate fichas por segundo. Cada requisição gasta
+// uma ficha; balde vazio ==> 429 Too Many Requests com Retry-After.
+//
+//	limiter := rateLimit.New(rateLimit.Options{
+//		Default: rateLimit.PerMinute(60, 10),
+//		Routes:  map[string]rateLimit.Limit{"POST /ceps": rateLimit.PerMinute(10, 2)},
+//	})
+//	http.ListenAndServe(":8080", limiter.Handler(mux))
+//
+// Rotas registradas com http.HandleFunc ficam no http.DefaultServeMux: passe
+// ele para o Handler, como nos servidores do 8_templates.
+
+// Limit é a vazão permitida: Rate fichas por segundo e rajadas de até Burst.
+// Rate <= 0 deixa a rota sem limite.
+type Limit struct {
+	Rate  float64
+	Burst int
+}
+
+// PerMinute monta um Limit de n requisições por minuto com rajadas de até burst
+func PerMinute(n, burst int) Limit {
+	return Limit{Rate: float64(n) / 60, Burst: max(burst, 1)}
+}
+
+// Options configura o Limiter
+type Options struct {
+	Default Limit            // Vale para as rotas sem limite próprio
+	Routes  map[string]Limit // Padrão do ServeMux ("POST /ceps", "/graphql") ==> limite próprio
+
+	// Key identifica o cliente (padrão: ClientIP; veja também APIKeyOrIP)
+	Key func(r *http.Request) string
+
+	// IdleTTL é quanto tempo um balde sem uso fica na memória (padrão 10 min).
+	// Um balde parado há tanto tempo já está cheio: apagar não muda nada para o cliente.
+	IdleTTL time.Duration
+
+	// MaxBuckets limita os baldes na memória (padrão 100000). Cheio, o balde usado
+	// há mais tempo é descartado: muitos IPs (ou chaves) novos não esgotam a memória
+	// antes do IdleTTL, ao custo de o cliente descartado recomeçar com o balde cheio.
+	MaxBuckets int
+}
+
+// Decision é o resultado de Allow, usado nos headers X-RateLimit-*
+type Decision struct {
+	Allowed    bool
+	Limit      int           // Tamanho do balde (Burst)
+	Remaining  int           // Fichas que sobraram
+	Reset      time.Duration // Até o balde encher de novo
+	RetryAfter time.Duration // Até a próxima ficha (só quando !Allowed)
+}
+
+// bucket guarda as fichas de um cliente em uma rota
+type bucket struct {
+	id     string // Chave em Limiter.buckets
+	tokens float64
+	last   time.Time // Última atualização de tokens
+}
+
+// Limiter guarda um balde por cliente (e por rota com limite próprio)
+type Limiter struct {
+	opts Options
+	now  func() time.Time // Trocado nos testes
+
+	mu      sync.Mutex
+	order   *list.List               // Frente = usado mais recentemente
+	buckets map[string]*list.Element // id ==> elemento de order (*bucket)
+}
+
+// New cria o Limiter; sem goroutine: os baldes parados são apagados durante os Allow
+func New(opts Options) *Limiter {
+	if opts.Key == nil {
+		opts.Key = ClientIP
+	}
+	if opts.IdleTTL <= 0 {
+		opts.IdleTTL = 10 * time.Minute
+	}
+	if opts.MaxBuckets <= 0 {
+		opts.MaxBuckets = 100000
+	}
+	return &Limiter{opts: opts, now: time.Now, order: list.New(), buckets: make(map[string]*list.Element)}
+}
+
+// Allow gasta uma ficha do cliente key na rota (padrão do ServeMux, ou "")
+func (l *Limiter) Allow(key, route string) Decision {
+	limit, own := l.opts.Routes[route]
+	if !own {
+		limit, route = l.opts.Default, "" // Rotas sem limite próprio dividem o mesmo balde
+	}
+	if limit.Rate <= 0 {
+		return Decision{Allowed: true}
+	}
+	burst := float64(max(limit.Burst, 1))
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	l.sweep(now)
+
+	b := l.bucket(key+" "+route, burst, now)
+	b.tokens = math.Min(burst, b.tokens+now.Sub(b.last).Seconds()*limit.Rate)
+	b.last = now
+
+	d := Decision{Limit: int(burst)}
+	if b.tokens >= 1 {
+		b.tokens--
+		d.Allowed = true
+	} else {
+		d.RetryAfter = seconds((1 - b.tokens) / limit.Rate)
+	}
+	d.Remaining = int(b.tokens)
+	d.Reset = seconds((burst - b.tokens) / limit.Rate)
+	return d
+}
+
+// bucket devolve o balde id, criando um cheio se não existe. Chamar com l.mu travado.
+func (l *Limiter) bucket(id string, burst float64, now time.Time) *bucket {
+	if elem, ok := l.buckets[id]; ok {
+		l.order.MoveToFront(elem)
+		return elem.Value.(*bucket)
+	}
+	if l.order.Len() >= l.opts.MaxBuckets {
+		l.remove(l.order.Back())
+	}
+	b := &bucket{id: id, tokens: burst, last: now}
+	l.buckets[id] = l.order.PushFront(b)
+	return b
+}
+
+// sweep apaga os baldes parados há mais de IdleTTL. Os parados ficam no fim
+// de order, então só percorre os que vão sair.
+func (l *Limiter) sweep(now time.Time) {
+	for elem := l.order.Back(); elem != nil && now.Sub(elem.Value.(*bucket).last) >= l.opts.IdleTTL; elem = l.order.Back() {
+		l.remove(elem)
+	}
+}
+
+func (l *Limiter) remove(elem *list.Element) {
+	l.order.Remove(elem)
+	delete(l.buckets, elem.Value.(*bucket).id)
+}
+
+// Len devolve quantos baldes estão na memória
+func (l *Limiter) Len() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return len(l.buckets)
+}
+
+// Handler aplica o limite antes de next. Se next for um *http.ServeMux, o
+// padrão da rota (ex.: "POST /ceps") escolhe o limite em Options.Routes.
+func (l *Limiter) Handler(next http.Handler) http.Handler {
+	mux, _ := next.(*http.ServeMux)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		route := ""
+		if mux != nil {
+			_, route = mux.Handler(r)
+		}
+
+		d := l.Allow(l.opts.Key(r), route)
+		if d.Limit > 0 {
+			w.Header().Set("X-RateLimit-Limit", strconv.Itoa(d.Limit))
+			w.Header().Set("X-RateLimit-Remaining", strconv.Itoa(d.Remaining))
+			w.Header().Set("X-RateLimit-Reset", strconv.Itoa(ceilSeconds(d.Reset)))
+		}
+		if !d.Allowed {
+			w.Header().Set("Retry-After", strconv.Itoa(max(ceilSeconds(d.RetryAfter), 1)))
+			http.Error(w, "muitas requisições, tente novamente mais tarde", http.StatusTooManyRequests)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// ClientIP identifica o cliente pelo IP da conexão.
+// X-Forwarded-For não é usado: qualquer cliente poderia forjar o header.
+func ClientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return "ip:" + r.RemoteAddr
+	}
+	return "ip:" + host
+}
+
+// APIKeyOrIP usa o header X-API-Key e, sem ele, o IP. Só use com chaves já
+// validadas antes do limiter: senão cada chave inventada ganha um balde cheio.
+func APIKeyOrIP(r *http.Request) string {
+	if key := r.Header.Get("X-API-Key"); key != "" {
+		return "key:" + key
+	}
+	return ClientIP(r)
+}
+
+func seconds(s float64) time.Duration {
+	return time.Duration(s * float64(time.Second))
+}
+
+func ceilSeconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
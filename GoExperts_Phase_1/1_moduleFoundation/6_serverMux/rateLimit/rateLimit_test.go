@@ -0,0 +1,180 @@
+package rateLimit
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// fakeClock é o relógio dos testes, avançado à mão
+type fakeClock struct{ t time.Time }
+
+func (c *fakeClock) now() time.Time          { return c.t }
+func (c *fakeClock) advance(d time.Duration) { c.t = c.t.Add(d) }
+
+func newTestLimiter(opts Options) (*Limiter, *fakeClock) {
+	clock := &fakeClock{t: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)}
+	l := New(opts)
+	l.now = clock.now
+	return l, clock
+}
+
+func TestAllow(t *testing.T) {
+	l, clock := newTestLimiter(Options{Default: Limit{Rate: 1, Burst: 3}})
+
+	// Rajada: as 3 fichas do balde
+	for i := range 3 {
+		if d := l.Allow("a", ""); !d.Allowed || d.Remaining != 2-i {
+			t.Fatalf("requisição %d = %+v; expect permitida com %d restantes", i+1, d, 2-i)
+		}
+	}
+	d := l.Allow("a", "")
+	if d.Allowed || d.RetryAfter != time.Second {
+		t.Fatalf("4ª requisição = %+v; expect bloqueada por 1s", d)
+	}
+
+	// Outro cliente tem o próprio balde
+	if d := l.Allow("b", ""); !d.Allowed {
+		t.Errorf("cliente b bloqueado pelo balde de a")
+	}
+
+	// 1 ficha por segundo
+	clock.advance(time.Second)
+	if d := l.Allow("a", ""); !d.Allowed {
+		t.Errorf("após 1s = %+v; expect permitida", d)
+	}
+	if d := l.Allow("a", ""); d.Allowed {
+		t.Errorf("2ª após 1s = %+v; expect bloqueada", d)
+	}
+
+	// Parado por muito tempo o balde enche só até o Burst
+	clock.advance(time.Hour)
+	if d := l.Allow("a", ""); d.Remaining != 2 || d.Reset != time.Second {
+		t.Errorf("após 1h = %+v; expect 2 restantes e reset em 1s", d)
+	}
+}
+
+func TestAllowRoutes(t *testing.T) {
+	l, _ := newTestLimiter(Options{
+		Default: Limit{Rate: 1, Burst: 5},
+		Routes: map[string]Limit{
+			"POST /ceps":  {Rate: 1, Burst: 1},
+			"GET /status": {}, // Sem limite
+		},
+	})
+
+	if !l.Allow("a", "POST /ceps").Allowed || l.Allow("a", "POST /ceps").Allowed {
+		t.Errorf("POST /ceps deveria permitir só 1")
+	}
+	// A rota com limite próprio não gasta o balde padrão
+	if d := l.Allow("a", "GET /{$}"); !d.Allowed || d.Remaining != 4 {
+		t.Errorf("rota padrão = %+v; expect 4 restantes", d)
+	}
+	for range 10 {
+		if d := l.Allow("a", "GET /status"); !d.Allowed || d.Limit != 0 {
+			t.Fatalf("GET /status = %+v; expect sem limite", d)
+		}
+	}
+}
+
+func TestEvictIdleBuckets(t *testing.T) {
+	l, clock := newTestLimiter(Options{Default: Limit{Rate: 1, Burst: 1}, IdleTTL: time.Minute})
+
+	for _, key := range []string{"a", "b", "c"} {
+		l.Allow(key, "")
+	}
+	clock.advance(30 * time.Second)
+	l.Allow("a", "")
+	if l.Len() != 3 {
+		t.Fatalf("%d baldes; expect 3", l.Len())
+	}
+
+	// b e c ficaram parados por 1 minuto; a foi usado há 30s
+	clock.advance(30 * time.Second)
+	l.Allow("d", "")
+	if l.Len() != 2 {
+		t.Errorf("%d baldes após o IdleTTL; expect 2 (a e d)", l.Len())
+	}
+}
+
+func TestMaxBuckets(t *testing.T) {
+	l, clock := newTestLimiter(Options{Default: Limit{Rate: 1, Burst: 2}, MaxBuckets: 2})
+
+	l.Allow("a", "")
+	l.Allow("b", "")
+	clock.advance(time.Millisecond)
+	l.Allow("a", "") // a passa a ser o mais recente: b é o próximo a sair
+	l.Allow("c", "")
+	if l.Len() != 2 {
+		t.Fatalf("%d baldes; expect 2 (MaxBuckets)", l.Len())
+	}
+
+	// a continua com o balde gasto; b foi descartado e recomeça cheio
+	if d := l.Allow("a", ""); d.Remaining != 0 {
+		t.Errorf("a Remaining = %d; expect 0 (balde mantido)", d.Remaining)
+	}
+	if d := l.Allow("b", ""); d.Remaining != 1 {
+		t.Errorf("b Remaining = %d; expect 1 (balde novo)", d.Remaining)
+	}
+}
+
+func TestHandler(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /", func(w http.ResponseWriter, r *http.Request) {})
+	mux.HandleFunc("POST /ceps", func(w http.ResponseWriter, r *http.Request) {})
+
+	l, _ := newTestLimiter(Options{
+		Default: Limit{Rate: 0.5, Burst: 2},
+		Routes:  map[string]Limit{"POST /ceps": {Rate: 0.5, Burst: 1}},
+	})
+	h := l.Handler(mux)
+
+	do := func(method, target, ip string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, target, nil)
+		req.RemoteAddr = ip + ":1234"
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		return rec
+	}
+
+	if rec := do(http.MethodPost, "/ceps", "10.0.0.1"); rec.Code != http.StatusOK {
+		t.Fatalf("1º POST /ceps = %d; expect 200", rec.Code)
+	}
+	rec := do(http.MethodPost, "/ceps", "10.0.0.1")
+	if rec.Code != http.StatusTooManyRequests {
+		t.Fatalf("2º POST /ceps = %d; expect 429", rec.Code)
+	}
+	expect := map[string]string{"Retry-After": "2", "X-RateLimit-Limit": "1", "X-RateLimit-Remaining": "0", "X-RateLimit-Reset": "2"}
+	for header, value := range expect {
+		if got := rec.Header().Get(header); got != value {
+			t.Errorf("%s = %q; expect %q", header, got, value)
+		}
+	}
+
+	// Mesmo IP, outra rota: balde padrão ainda cheio
+	if rec := do(http.MethodGet, "/", "10.0.0.1"); rec.Code != http.StatusOK || rec.Header().Get("X-RateLimit-Remaining") != "1" {
+		t.Errorf("GET / = %d (restantes %s); expect 200 com 1 restante", rec.Code, rec.Header().Get("X-RateLimit-Remaining"))
+	}
+	// Outro IP não é afetado
+	if rec := do(http.MethodPost, "/ceps", "10.0.0.2"); rec.Code != http.StatusOK {
+		t.Errorf("POST /ceps de outro IP = %d; expect 200", rec.Code)
+	}
+}
+
+func TestClientKeys(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.RemoteAddr = "192.168.0.10:5555"
+	req.Header.Set("X-Forwarded-For", "1.2.3.4")
+
+	if got := ClientIP(req); got != "ip:192.168.0.10" {
+		t.Errorf("ClientIP() = %q; expect ip:192.168.0.10", got)
+	}
+	if got := APIKeyOrIP(req); got != "ip:192.168.0.10" {
+		t.Errorf("APIKeyOrIP() sem chave = %q; expect o IP", got)
+	}
+	req.Header.Set("X-API-Key", "abc")
+	if got := APIKeyOrIP(req); got != "key:abc" {
+		t.Errorf("APIKeyOrIP() = %q; expect key:abc", got)
+	}
+}
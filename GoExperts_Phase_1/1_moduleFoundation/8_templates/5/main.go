@@ -1,6 +1,7 @@
 package main
 
 import (
+	"GoProject/1_moduleFoundation/6_serverMux/rateLimit"
 	"net/http"
 	"text/template"
 )
@@ -31,6 +32,7 @@ func main() {
             panic(err)
         }
     })
-    http.ListenAndServe(":8282", nil)
+    limiter := rateLimit.New(rateLimit.Options{Default: rateLimit.PerMinute(60, 10)})
+    http.ListenAndServe(":8282", limiter.Handler(http.DefaultServeMux))
 }
 
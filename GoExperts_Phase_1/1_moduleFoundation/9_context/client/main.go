@@ -6,6 +6,8 @@ import (
 	"net/http"
 	"os"
 	"time"
+
+	"GoProject/1_moduleFoundation/2_HTTPClient/httpClient"
 )
 
 func main() {
@@ -14,13 +16,20 @@ func main() {
 	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
 	defer cancel()
 
+	// O client do pacote httpClient registra cada tentativa no log (status, duração e request ID).
+	// O limite da chamada fica com o ctx: o servidor leva 9s para responder.
+	client := httpClient.New(httpClient.Options{
+		UserAgent:   "9_context-client/1.0",
+		Middlewares: []httpClient.Middleware{httpClient.Logging(nil)},
+	})
+
 	// CLIENT - Requisição feita para o servidor da porta 8080
 	req, err := http.NewRequestWithContext(ctx, "GET", "http://localhost:8080", nil)
 	if err != nil {
 		panic(err)
 	}
 
-	res, err := http.DefaultClient.Do(req)
+	res, err := client.Do(req)
 	if err != nil {
 		panic(err)
 	}
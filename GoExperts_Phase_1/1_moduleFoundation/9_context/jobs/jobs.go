@@ -0,0 +1,238 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Motivos de cancelamento (context.Cause do ctx do job)
+var (
+	errCanceled     = errors.New("cancelado pelo cliente (DELETE)")
+	errDisconnected = errors.New("cliente desconectou")
+	errShutdown     = errors.New("servidor desligando")
+)
+
+// errTooManyJobs é devolvido por start quando já há maxRunning jobs em andamento
+var errTooManyJobs = errors.New("limite de jobs em andamento atingido, tente novamente mais tarde")
+
+// Status de um job
+const (
+	statusRunning  = "running"
+	statusDone     = "done"
+	statusCanceled = "canceled"
+	statusFailed   = "failed"
+)
+
+// workFunc é o trabalho do job: deve chamar report a cada passo concluído e
+// parar assim que o ctx for cancelado
+type workFunc func(ctx context.Context, steps int, report func(step int)) (string, error)
+
+// simulate faz o mesmo que o 9_context/server: cada passo espera step ou o cancelamento
+func simulate(step time.Duration) workFunc {
+	return func(ctx context.Context, steps int, report func(int)) (string, error) {
+		for i := 1; i <= steps; i++ {
+			select {
+			case <-time.After(step):
+				report(i)
+			case <-ctx.Done():
+				return "", context.Cause(ctx)
+			}
+		}
+		return fmt.Sprintf("Request processada com sucesso em %d passos", steps), nil
+	}
+}
+
+// snapshot é o estado de um job num instante, como vai no JSON e nos eventos SSE
+type snapshot struct {
+	ID         string     `json:"id"`
+	Status     string     `json:"status"`
+	Step       int        `json:"step"`
+	Steps      int        `json:"steps"`
+	Progress   int        `json:"progress"` // Percentual
+	Attached   bool       `json:"attached"` // Cancelado se o cliente do POST desconectar
+	Result     string     `json:"result,omitempty"`
+	Error      string     `json:"error,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+	FinishedAt *time.Time `json:"finished_at,omitempty"`
+
+	version int // Cresce a cada mudança; vira o id: do evento SSE
+}
+
+func (s snapshot) finished() bool { return s.Status != statusRunning }
+
+// job é um trabalho em andamento ou concluído
+type job struct {
+	cancel context.CancelCauseFunc
+
+	mu      sync.Mutex
+	state   snapshot
+	changed chan struct{} // Fechado (e trocado) a cada mudança de state
+}
+
+// watch devolve o estado atual e um canal fechado na próxima mudança
+func (j *job) watch() (snapshot, <-chan struct{}) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return j.state, j.changed
+}
+
+// update aplica a mudança e acorda quem está em watch
+func (j *job) update(change func(s *snapshot)) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	change(&j.state)
+	j.state.version++
+	close(j.changed)
+	j.changed = make(chan struct{})
+}
+
+// manager guarda os jobs; os concluídos ficam disponíveis por retention.
+// No máximo maxRunning jobs rodam ao mesmo tempo: cada um é uma goroutine
+// trabalhando, e um cliente sozinho não pode disparar trabalho sem limite.
+type manager struct {
+	ctx        context.Context // Cancelado no desligamento: encerra todos os jobs
+	work       workFunc
+	retention  time.Duration
+	maxRunning int
+	now        func() time.Time // Trocado nos testes
+
+	mu        sync.Mutex
+	jobs      map[string]*job
+	active    int // Jobs em andamento
+	lastSweep time.Time
+	running   sync.WaitGroup
+}
+
+func newManager(ctx context.Context, work workFunc, retention time.Duration, maxRunning int) *manager {
+	return &manager{ctx: ctx, work: work, retention: retention, maxRunning: max(maxRunning, 1), now: time.Now, jobs: make(map[string]*job)}
+}
+
+// start dispara o job. Com parent != nil (attach) o job também termina quando
+// parent é cancelado, ex.: o r.Context() de quem fez o POST. Sem parent o job
+// só para por DELETE ou no desligamento. Devolve errTooManyJobs se já há
+// maxRunning jobs em andamento.
+func (m *manager) start(parent context.Context, steps int) (*job, error) {
+	m.mu.Lock()
+	if m.active >= m.maxRunning {
+		m.mu.Unlock()
+		return nil, errTooManyJobs
+	}
+	m.active++
+	m.mu.Unlock()
+
+	attached := parent != nil
+	if !attached {
+		parent = context.Background()
+	}
+	ctx, cancel := context.WithCancelCause(parent)
+	stop := context.AfterFunc(m.ctx, func() { cancel(errShutdown) })
+
+	j := &job{
+		cancel:  cancel,
+		changed: make(chan struct{}),
+		state: snapshot{
+			ID:        uuid.NewString(),
+			Status:    statusRunning,
+			Steps:     steps,
+			Attached:  attached,
+			CreatedAt: m.now().UTC(),
+		},
+	}
+
+	m.mu.Lock()
+	m.sweep()
+	m.jobs[j.state.ID] = j
+	m.mu.Unlock()
+
+	m.running.Add(1)
+	go m.run(ctx, j, stop)
+	return j, nil
+}
+
+// run executa o trabalho e grava o resultado
+func (m *manager) run(ctx context.Context, j *job, stop func() bool) {
+	defer m.running.Done()
+	defer stop()
+
+	result, err := m.work(ctx, j.state.Steps, func(step int) {
+		j.update(func(s *snapshot) {
+			s.Step = step
+			s.Progress = step * 100 / max(s.Steps, 1)
+		})
+	})
+
+	canceled := err != nil && ctx.Err() != nil
+	if canceled {
+		// Só o ctx do attach é cancelado sem causa própria: é o cliente que desconectou
+		if err = context.Cause(ctx); errors.Is(err, context.Canceled) {
+			err = errDisconnected
+		}
+	}
+	j.cancel(nil) // Libera o ctx
+
+	// Libera a vaga antes do status final: quem vê o job concluído já pode criar outro
+	m.mu.Lock()
+	m.active--
+	m.mu.Unlock()
+
+	finished := m.now().UTC()
+	j.update(func(s *snapshot) {
+		s.FinishedAt = &finished
+		switch {
+		case err == nil:
+			s.Status, s.Result, s.Progress = statusDone, result, 100
+		case canceled:
+			s.Status, s.Error = statusCanceled, err.Error()
+		default:
+			s.Status, s.Error = statusFailed, err.Error()
+		}
+	})
+}
+
+// get devolve o job; false se não existe ou já passou da retenção
+func (m *manager) get(id string) (*job, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.sweep()
+	j, ok := m.jobs[id]
+	return j, ok
+}
+
+// list devolve o estado de todos os jobs guardados
+func (m *manager) list() []snapshot {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.sweep()
+	list := make([]snapshot, 0, len(m.jobs))
+	for _, j := range m.jobs {
+		s, _ := j.watch()
+		list = append(list, s)
+	}
+	return list
+}
+
+// sweep remove os jobs concluídos há mais de retention.
+// Preguiçoso, como no rateLimit: roda no máximo 1 vez por segundo, junto das
+// chamadas. Chamar com m.mu travado.
+func (m *manager) sweep() {
+	now := m.now()
+	if now.Sub(m.lastSweep) < time.Second {
+		return
+	}
+	m.lastSweep = now
+	for id, j := range m.jobs {
+		if s, _ := j.watch(); s.FinishedAt != nil && now.Sub(*s.FinishedAt) > m.retention {
+			delete(m.jobs, id)
+		}
+	}
+}
+
+// wait espera os jobs em andamento terminarem (após cancelar m.ctx)
+func (m *manager) wait() {
+	m.running.Wait()
+}
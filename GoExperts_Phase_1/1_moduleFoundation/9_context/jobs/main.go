@@ -0,0 +1,213 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"slices"
+	"strconv"
+	"syscall"
+	"time"
+)
+
+// API de jobs longos: a evolução do 9_context/server, onde o cliente não via
+// o progresso e só podia esperar os 9 segundos ou desistir.
+//
+//	POST   /jobs                  inicia um job ({"steps": 9}); 202 com o job e Location, 503 com -max-running em andamento
+//	POST   /jobs?attach=true      inicia e já transmite os eventos; se o cliente desconectar, o job é cancelado
+//	GET    /jobs                  lista os jobs guardados
+//	GET    /jobs/{id}             estado e resultado (concluídos ficam por -retention)
+//	GET    /jobs/{id}/events      progresso por Server-Sent Events; desconectar não cancela o job
+//	DELETE /jobs/{id}             cancela o job
+//
+// curl -N -X POST "localhost:8080/jobs?attach=true"   (Ctrl+C cancela o job)
+// curl -X POST localhost:8080/jobs -d '{"steps":5}' && curl -N localhost:8080/jobs/<id>/events
+
+// maxSteps limita o tamanho de um job pedido pelo cliente
+const maxSteps = 100
+
+// keepAlive é o intervalo dos comentários SSE que mantêm a conexão aberta em proxies
+var keepAlive = 15 * time.Second
+
+func main() {
+	addr := flag.String("addr", ":8080", "endereço do servidor http")
+	step := flag.Duration("step", time.Second, "duração de cada passo do job simulado")
+	retention := flag.Duration("retention", 10*time.Minute, "por quanto tempo um job concluído fica disponível")
+	maxRunning := flag.Int("max-running", 10, "máximo de jobs em andamento ao mesmo tempo")
+	flag.Parse()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	jobs := newManager(ctx, simulate(*step), *retention, *maxRunning)
+	server := &http.Server{Addr: *addr, Handler: jobs.routes()}
+	go func() {
+		log.Printf("Servidor ouvindo em %s", *addr)
+		if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Fatalf("Erro ao subir servidor: %v", err)
+		}
+	}()
+
+	// Ctrl+C cancela o ctx dos jobs: eles terminam como canceled e os streams
+	// SSE recebem o evento final antes do Shutdown fechar as conexões
+	<-ctx.Done()
+	jobs.wait()
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		log.Printf("Erro ao desligar servidor: %v", err)
+	}
+}
+
+func (m *manager) routes() *http.ServeMux {
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /jobs", m.createHandler)
+	mux.HandleFunc("GET /jobs", m.listHandler)
+	mux.HandleFunc("GET /jobs/{id}", m.getHandler)
+	mux.HandleFunc("GET /jobs/{id}/events", m.eventsHandler)
+	mux.HandleFunc("DELETE /jobs/{id}", m.deleteHandler)
+	return mux
+}
+
+// createRequest é o corpo (opcional) do POST /jobs
+type createRequest struct {
+	Steps int `json:"steps"`
+}
+
+func (m *manager) createHandler(w http.ResponseWriter, r *http.Request) {
+	req := createRequest{Steps: 9}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		writeJSONError(w, http.StatusBadRequest, "JSON inválido: "+err.Error())
+		return
+	}
+	if req.Steps < 1 || req.Steps > maxSteps {
+		writeJSONError(w, http.StatusBadRequest, fmt.Sprintf("steps deve estar entre 1 e %d", maxSteps))
+		return
+	}
+	attach, _ := strconv.ParseBool(r.URL.Query().Get("attach"))
+
+	// Attach: o job vive enquanto esta requisição estiver aberta
+	var parent context.Context
+	if attach {
+		parent = r.Context()
+	}
+	j, err := m.start(parent, req.Steps)
+	if err != nil { // errTooManyJobs
+		writeJSONError(w, http.StatusServiceUnavailable, err.Error())
+		return
+	}
+	s, _ := j.watch()
+
+	if !attach {
+		log.Printf("Job %s iniciado (%d passos)", s.ID, s.Steps)
+		w.Header().Set("Location", "/jobs/"+s.ID)
+		writeJSON(w, http.StatusAccepted, s)
+		return
+	}
+
+	log.Printf("Job %s iniciado com attach (%d passos)", s.ID, s.Steps)
+	w.Header().Set("Location", "/jobs/"+s.ID)
+	streamEvents(w, r, j)
+}
+
+func (m *manager) listHandler(w http.ResponseWriter, r *http.Request) {
+	list := m.list()
+	slices.SortFunc(list, func(a, b snapshot) int { return a.CreatedAt.Compare(b.CreatedAt) })
+	writeJSON(w, http.StatusOK, list)
+}
+
+func (m *manager) getHandler(w http.ResponseWriter, r *http.Request) {
+	j, ok := m.get(r.PathValue("id"))
+	if !ok {
+		writeJSONError(w, http.StatusNotFound, "job não encontrado")
+		return
+	}
+	s, _ := j.watch()
+	writeJSON(w, http.StatusOK, s)
+}
+
+func (m *manager) eventsHandler(w http.ResponseWriter, r *http.Request) {
+	j, ok := m.get(r.PathValue("id"))
+	if !ok {
+		writeJSONError(w, http.StatusNotFound, "job não encontrado")
+		return
+	}
+	streamEvents(w, r, j)
+}
+
+// deleteHandler cancela o job; o status vira canceled quando o trabalho parar
+func (m *manager) deleteHandler(w http.ResponseWriter, r *http.Request) {
+	j, ok := m.get(r.PathValue("id"))
+	if !ok {
+		writeJSONError(w, http.StatusNotFound, "job não encontrado")
+		return
+	}
+	s, _ := j.watch()
+	if s.finished() {
+		writeJSONError(w, http.StatusConflict, "job já concluído: "+s.Status)
+		return
+	}
+	j.cancel(errCanceled)
+	log.Printf("Job %s cancelado pelo cliente", s.ID)
+	writeJSON(w, http.StatusAccepted, s)
+}
+
+// streamEvents transmite o estado do job por SSE até ele terminar ou o cliente
+// sair. Cada mudança vira um evento "progress"; o último leva o status final
+// (done, canceled ou failed). Sair daqui não cancela o job: só o attach faz isso,
+// pelo ctx da própria requisição.
+func streamEvents(w http.ResponseWriter, r *http.Request, j *job) {
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("X-Accel-Buffering", "no") // nginx não deve segurar os eventos
+	w.WriteHeader(http.StatusOK)
+	rc := http.NewResponseController(w)
+
+	ping := time.NewTicker(keepAlive)
+	defer ping.Stop()
+
+	for {
+		s, changed := j.watch()
+		event := "progress"
+		if s.finished() {
+			event = s.Status
+		}
+		data, _ := json.Marshal(s)
+		fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", s.version, event, data)
+		if err := rc.Flush(); err != nil || s.finished() {
+			return
+		}
+
+		for waiting := true; waiting; {
+			select {
+			case <-changed:
+				waiting = false
+			case <-ping.C:
+				fmt.Fprint(w, ": ping\n\n")
+				if rc.Flush() != nil {
+					return
+				}
+			case <-r.Context().Done():
+				return
+			}
+		}
+	}
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeJSONError(w http.ResponseWriter, status int, msg string) {
+	writeJSON(w, status, map[string]string{"error": msg})
+}
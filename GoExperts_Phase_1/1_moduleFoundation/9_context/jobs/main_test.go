@@ -0,0 +1,293 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// newTestServer sobe a API com passos de 10ms e até 2 jobs em andamento
+func newTestServer(t *testing.T) (*manager, *httptest.Server, context.CancelFunc) {
+	t.Helper()
+	ctx, cancel := context.WithCancel(context.Background())
+	m := newManager(ctx, simulate(10*time.Millisecond), time.Minute, 2)
+	server := httptest.NewServer(m.routes())
+	t.Cleanup(func() {
+		cancel()
+		m.wait()
+		server.Close()
+	})
+	return m, server, cancel
+}
+
+type event struct {
+	name string
+	job  snapshot
+}
+
+// readEvents lê os eventos SSE até o stream fechar
+func readEvents(t *testing.T, resp *http.Response) []event {
+	t.Helper()
+	defer resp.Body.Close()
+	if ct := resp.Header.Get("Content-Type"); ct != "text/event-stream" {
+		t.Fatalf("Content-Type = %q; expect text/event-stream", ct)
+	}
+
+	var events []event
+	var current event
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case strings.HasPrefix(line, "event: "):
+			current.name = strings.TrimPrefix(line, "event: ")
+		case strings.HasPrefix(line, "data: "):
+			json.Unmarshal([]byte(strings.TrimPrefix(line, "data: ")), &current.job)
+		case line == "" && current.name != "":
+			events = append(events, current)
+			current = event{}
+		}
+	}
+	return events
+}
+
+func postJob(t *testing.T, url, body string) snapshot {
+	t.Helper()
+	resp, err := http.Post(url+"/jobs", "application/json", strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	var s snapshot
+	json.NewDecoder(resp.Body).Decode(&s)
+	if resp.StatusCode != http.StatusAccepted || resp.Header.Get("Location") != "/jobs/"+s.ID {
+		t.Fatalf("POST /jobs = %d, Location %q; expect 202 e /jobs/%s", resp.StatusCode, resp.Header.Get("Location"), s.ID)
+	}
+	return s
+}
+
+func getJob(t *testing.T, url, id string) (snapshot, int) {
+	t.Helper()
+	resp, err := http.Get(url + "/jobs/" + id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	var s snapshot
+	json.NewDecoder(resp.Body).Decode(&s)
+	return s, resp.StatusCode
+}
+
+func TestJobEvents(t *testing.T) {
+	_, server, _ := newTestServer(t)
+	job := postJob(t, server.URL, `{"steps":3}`)
+
+	resp, err := http.Get(server.URL + "/jobs/" + job.ID + "/events")
+	if err != nil {
+		t.Fatal(err)
+	}
+	events := readEvents(t, resp)
+
+	last := events[len(events)-1]
+	if last.name != statusDone || last.job.Progress != 100 || last.job.Result == "" {
+		t.Fatalf("último evento = %+v; expect done com resultado", last)
+	}
+	for i, e := range events[:len(events)-1] {
+		if e.name != "progress" || (i > 0 && e.job.Step < events[i-1].job.Step) {
+			t.Errorf("evento %d = %+v; expect progress crescente", i, e)
+		}
+	}
+
+	// O resultado continua disponível depois do stream
+	if s, code := getJob(t, server.URL, job.ID); code != http.StatusOK || s.Status != statusDone || s.FinishedAt == nil {
+		t.Errorf("GET /jobs/{id} = %d %+v; expect 200 done", code, s)
+	}
+}
+
+func TestJobDelete(t *testing.T) {
+	_, server, _ := newTestServer(t)
+	job := postJob(t, server.URL, `{"steps":100}`)
+
+	events := make(chan []event)
+	go func() {
+		resp, err := http.Get(server.URL + "/jobs/" + job.ID + "/events")
+		if err != nil {
+			events <- nil
+			return
+		}
+		events <- readEvents(t, resp)
+	}()
+
+	time.Sleep(30 * time.Millisecond)
+	req, _ := http.NewRequest(http.MethodDelete, server.URL+"/jobs/"+job.ID, nil)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusAccepted {
+		t.Fatalf("DELETE = %d; expect 202", resp.StatusCode)
+	}
+
+	got := <-events
+	last := got[len(got)-1]
+	if last.name != statusCanceled || last.job.Error != errCanceled.Error() || last.job.Step >= 100 {
+		t.Errorf("último evento = %+v; expect canceled por DELETE", last)
+	}
+
+	// Cancelar de novo: já concluído
+	resp, _ = http.DefaultClient.Do(req)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusConflict {
+		t.Errorf("2º DELETE = %d; expect 409", resp.StatusCode)
+	}
+}
+
+func TestJobDisconnect(t *testing.T) {
+	tests := []struct {
+		name   string
+		attach bool
+		status string
+	}{
+		{"sem attach o job continua", false, statusDone},
+		{"com attach o job é cancelado", true, statusCanceled},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, server, _ := newTestServer(t)
+
+			// Abre o stream (POST com attach ou GET /events) e desconecta após o 1º evento
+			var id string
+			if tt.attach {
+				ctx, cancel := context.WithCancel(context.Background())
+				req, _ := http.NewRequestWithContext(ctx, http.MethodPost, server.URL+"/jobs?attach=true", strings.NewReader(`{"steps":5}`))
+				resp, err := http.DefaultClient.Do(req)
+				if err != nil {
+					t.Fatal(err)
+				}
+				id = strings.TrimPrefix(resp.Header.Get("Location"), "/jobs/")
+				bufio.NewReader(resp.Body).ReadString('\n')
+				cancel()
+				resp.Body.Close()
+			} else {
+				id = postJob(t, server.URL, `{"steps":5}`).ID
+				ctx, cancel := context.WithCancel(context.Background())
+				req, _ := http.NewRequestWithContext(ctx, http.MethodGet, server.URL+"/jobs/"+id+"/events", nil)
+				resp, err := http.DefaultClient.Do(req)
+				if err != nil {
+					t.Fatal(err)
+				}
+				bufio.NewReader(resp.Body).ReadString('\n')
+				cancel()
+				resp.Body.Close()
+			}
+
+			j, ok := m.get(id)
+			if !ok {
+				t.Fatalf("job %q não encontrado", id)
+			}
+			for s, changed := j.watch(); !s.finished(); s, changed = j.watch() {
+				<-changed
+			}
+			s, _ := getJob(t, server.URL, id)
+			if s.Status != tt.status || s.Attached != tt.attach {
+				t.Errorf("job = %+v; expect %s", s, tt.status)
+			}
+			if tt.attach && s.Error != errDisconnected.Error() {
+				t.Errorf("erro = %q; expect %q", s.Error, errDisconnected)
+			}
+		})
+	}
+}
+
+func TestJobShutdown(t *testing.T) {
+	m, server, cancel := newTestServer(t)
+	job := postJob(t, server.URL, `{"steps":100}`)
+
+	cancel()
+	m.wait()
+	if s, _ := getJob(t, server.URL, job.ID); s.Status != statusCanceled || s.Error != errShutdown.Error() {
+		t.Errorf("job após desligar = %+v; expect canceled por desligamento", s)
+	}
+}
+
+func TestJobRetention(t *testing.T) {
+	m, server, _ := newTestServer(t)
+	var mu sync.Mutex
+	now := time.Now()
+	m.mu.Lock()
+	m.now = func() time.Time { mu.Lock(); defer mu.Unlock(); return now }
+	m.mu.Unlock()
+
+	job := postJob(t, server.URL, `{"steps":1}`)
+	m.wait()
+
+	if _, code := getJob(t, server.URL, job.ID); code != http.StatusOK {
+		t.Fatalf("GET logo após concluir = %d; expect 200", code)
+	}
+	mu.Lock()
+	now = now.Add(2 * time.Minute)
+	mu.Unlock()
+	if _, code := getJob(t, server.URL, job.ID); code != http.StatusNotFound {
+		t.Errorf("GET após a retenção = %d; expect 404", code)
+	}
+}
+
+func TestCreateJobValidation(t *testing.T) {
+	_, server, _ := newTestServer(t)
+
+	for _, body := range []string{`{"steps":0}`, `{"steps":101}`, `{`} {
+		resp, err := http.Post(server.URL+"/jobs", "application/json", strings.NewReader(body))
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusBadRequest {
+			t.Errorf("POST %s = %d; expect 400", body, resp.StatusCode)
+		}
+	}
+	if _, code := getJob(t, server.URL, "nao-existe"); code != http.StatusNotFound {
+		t.Errorf("GET de job inexistente = %d; expect 404", code)
+	}
+}
+
+func TestJobLimit(t *testing.T) {
+	_, server, _ := newTestServer(t)
+	first := postJob(t, server.URL, `{"steps":100}`)
+	postJob(t, server.URL, `{"steps":100}`)
+
+	for _, target := range []string{"/jobs", "/jobs?attach=true"} {
+		resp, err := http.Post(server.URL+target, "application/json", strings.NewReader(`{"steps":1}`))
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusServiceUnavailable {
+			t.Errorf("POST %s com 2 jobs em andamento = %d; expect 503", target, resp.StatusCode)
+		}
+	}
+
+	// Cancelar um job libera a vaga assim que ele aparece como concluído
+	req, _ := http.NewRequest(http.MethodDelete, server.URL+"/jobs/"+first.ID, nil)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	for deadline := time.Now().Add(time.Second); ; time.Sleep(5 * time.Millisecond) {
+		if s, _ := getJob(t, server.URL, first.ID); s.finished() {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("job cancelado não terminou")
+		}
+	}
+	postJob(t, server.URL, `{"steps":1}`)
+}